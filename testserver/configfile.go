@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"testserver/server"
+)
+
+// fileConfig is the layout of a -config file.
+type fileConfig struct {
+	// Listeners are served together in place of -addr, -tls-cert and
+	// -tls-key.
+	Listeners []server.Listener `json:"listeners"`
+}
+
+// loadConfigFile reads and decodes the JSON config file at path, rejecting
+// unknown keys so typos are not silently ignored.
+func loadConfigFile(path string) (fileConfig, error) {
+	var fc fileConfig
+	f, err := os.Open(path)
+	if err != nil {
+		return fc, fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return fc, fmt.Errorf("config file %s: %w", path, err)
+	}
+	if len(fc.Listeners) == 0 {
+		return fc, fmt.Errorf("config file %s: no listeners defined", path)
+	}
+	return fc, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"testserver/server"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "friend.json")
+	err := os.WriteFile(path, []byte(`{"listeners": [
+		{"addr": ":8080"},
+		{"addr": ":8443", "tls_cert": "cert.pem", "tls_key": "key.pem"}
+	]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := parseConfig([]string{"-config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []server.Listener{{Addr: ":8080"}, {Addr: ":8443", TLSCert: "cert.pem", TLSKey: "key.pem"}}
+	if !slices.Equal(cfg.Listeners, want) {
+		t.Errorf("Listeners = %+v, want %+v", cfg.Listeners, want)
+	}
+}
+
+func TestParseConfigFileInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"malformed":    `{"listeners": [`,
+		"unknown key":  `{"listeners": [{"address": ":8080"}]}`,
+		"no listeners": `{"listeners": []}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "friend.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parseConfig([]string{"-config", path}); err == nil {
+				t.Error("parseConfig succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envOr returns the value of the environment variable key, or fallback when
+// it is unset or empty.
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envKey returns the environment variable that supplies the default for the
+// flag with the given name: "max-body" reads FRIEND_MAX_BODY.
+func envKey(flagName string) string {
+	return "FRIEND_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// envDefaults reads typed flag defaults from the environment and remembers
+// the variables that fail to parse, in the order they were read.
+type envDefaults struct {
+	failures []envFailure
+}
+
+type envFailure struct {
+	key string
+	err error
+}
+
+func (e *envDefaults) duration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		e.fail(key, v, "a duration such as 30s")
+		return fallback
+	}
+	return d
+}
+
+func (e *envDefaults) int(key string, fallback int) int {
+	return int(e.int64(key, int64(fallback)))
+}
+
+func (e *envDefaults) int64(key string, fallback int64) int64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		e.fail(key, v, "an integer")
+		return fallback
+	}
+	return n
+}
+
+func (e *envDefaults) float64(key string, fallback float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		e.fail(key, v, "a number")
+		return fallback
+	}
+	return f
+}
+
+func (e *envDefaults) bool(key string, fallback bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		e.fail(key, v, "true or false")
+		return fallback
+	}
+	return b
+}
+
+func (e *envDefaults) fail(key, value, want string) {
+	e.failures = append(e.failures, envFailure{key, fmt.Errorf("invalid %s=%q: want %s", key, value, want)})
+}
+
+// err returns the first parse failure for a variable whose flag was not set
+// on the command line, since a flag overrides its variable entirely.
+func (e *envDefaults) err(fs *flag.FlagSet) error {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[envKey(f.Name)] = true })
+	for _, f := range e.failures {
+		if !set[f.key] {
+			return f.err
+		}
+	}
+	return nil
+}
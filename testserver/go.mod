@@ -1,3 +1,9 @@
 module testserver
 
 go 1.25.5
+
+require (
+	github.com/andybalholm/brotli v1.2.1
+	golang.org/x/net v0.55.0
+	golang.org/x/time v0.14.0
+)
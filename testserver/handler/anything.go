@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"unicode/utf8"
+)
+
+// RequestDump is everything HandleAnything reports about a request.
+type RequestDump struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	Args    url.Values  `json:"args"`
+	Form    url.Values  `json:"form"`
+	Body    string      `json:"body"`
+	// BodyEncoding is "base64" when Body had to be encoded because the raw
+	// body was not valid UTF-8, and "utf-8" otherwise.
+	BodyEncoding string `json:"body_encoding"`
+}
+
+// HandleAnything replies with a JSON RequestDump of the request: method, URL,
+// headers, query arguments, form values and the raw body.
+func HandleAnything(w http.ResponseWriter, r *http.Request) {
+	// Read the raw body before parsing the form, then hand ParseForm a copy:
+	// it would otherwise consume the body and leave nothing to report.
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	dump := RequestDump{
+		Method:  r.Method,
+		URL:     scheme + "://" + r.Host + r.URL.RequestURI(),
+		Headers: r.Header,
+		Args:    r.URL.Query(),
+		Form:    r.PostForm,
+	}
+	dump.Body, dump.BodyEncoding = encodeBody(body)
+
+	out, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(out, '\n'))
+}
+
+// encodeBody returns body as a JSON-safe string along with its encoding:
+// "utf-8" when it can be used as-is, and "base64" otherwise.
+func encodeBody(body []byte) (string, string) {
+	if !utf8.Valid(body) {
+		return base64.StdEncoding.EncodeToString(body), "base64"
+	}
+	return string(body), "utf-8"
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleAnything(t *testing.T) {
+	const form = "name=friend&tags=a&tags=b"
+	req := httptest.NewRequest(http.MethodPost, "/anything/x?debug=1", strings.NewReader(form))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Probe", "yes")
+	rec := httptest.NewRecorder()
+	HandleAnything(rec, req)
+
+	var dump RequestDump
+	if err := json.Unmarshal(rec.Body.Bytes(), &dump); err != nil {
+		t.Fatal(err)
+	}
+	if dump.Method != http.MethodPost || dump.URL != "http://example.com/anything/x?debug=1" {
+		t.Errorf("method %q url %q", dump.Method, dump.URL)
+	}
+	if dump.Headers.Get("X-Probe") != "yes" {
+		t.Errorf("headers = %v, want X-Probe", dump.Headers)
+	}
+	if dump.Args.Get("debug") != "1" {
+		t.Errorf("args = %v, want debug=1", dump.Args)
+	}
+	if dump.Form.Get("name") != "friend" || len(dump.Form["tags"]) != 2 {
+		t.Errorf("form = %v, want name and both tags", dump.Form)
+	}
+	if dump.Body != form || dump.BodyEncoding != "utf-8" {
+		t.Errorf("body = %q (%s), want the raw form body", dump.Body, dump.BodyEncoding)
+	}
+}
+
+func TestHandleAnythingBinaryBody(t *testing.T) {
+	raw := []byte{0xff, 0xfe, 0x00}
+	rec := httptest.NewRecorder()
+	HandleAnything(rec, httptest.NewRequest(http.MethodPut, "/anything", bytes.NewReader(raw)))
+
+	var dump RequestDump
+	if err := json.Unmarshal(rec.Body.Bytes(), &dump); err != nil {
+		t.Fatal(err)
+	}
+	if dump.BodyEncoding != "base64" || dump.Body != base64.StdEncoding.EncodeToString(raw) {
+		t.Errorf("body = %q (%s), want base64 of the raw bytes", dump.Body, dump.BodyEncoding)
+	}
+}
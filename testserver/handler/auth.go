@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// BasicAuthMiddleware requires HTTP basic auth credentials matching user and
+// password, replying 401 with a challenge otherwise.
+func BasicAuthMiddleware(user, password string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			u, p, ok := r.BasicAuth()
+			// Evaluate both comparisons so a wrong user name takes as long
+			// as a wrong password.
+			userOK := subtle.ConstantTimeCompare([]byte(u), []byte(user))
+			passOK := subtle.ConstantTimeCompare([]byte(p), []byte(password))
+			if !ok || userOK&passOK != 1 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="friend"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBasicAuthMiddleware(t *testing.T) {
+	h := BasicAuthMiddleware("alice", "s3cret")(http.HandlerFunc(HandleEcho))
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		noAuth   bool
+		want     int
+	}{
+		{"no header", "", "", true, http.StatusUnauthorized},
+		{"wrong password", "alice", "guess", false, http.StatusUnauthorized},
+		{"wrong user", "bob", "s3cret", false, http.StatusUnauthorized},
+		{"correct", "alice", "s3cret", false, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if !tt.noAuth {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			challenge := rec.Header().Get("WWW-Authenticate")
+			if tt.want == http.StatusUnauthorized && challenge != `Basic realm="friend"` {
+				t.Errorf("WWW-Authenticate = %q, want the friend realm challenge", challenge)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HandleBase64 decodes the {value} path segment as URL-safe base64 and
+// replies with the decoded bytes. Trailing padding is optional.
+func HandleBase64(w http.ResponseWriter, r *http.Request) {
+	value := r.PathValue("value")
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid base64 %q: want URL-safe base64", value), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Write(data)
+}
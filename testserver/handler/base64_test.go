@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleBase64(t *testing.T) {
+	tests := []struct {
+		value    string
+		want     int
+		wantBody string
+	}{
+		{"aGVsbG8sIGZyaWVuZA==", http.StatusOK, "hello, friend"},
+		{"aGVsbG8sIGZyaWVuZA", http.StatusOK, "hello, friend"},
+		{"Pz8_", http.StatusOK, "???"},
+		{"not*base64", http.StatusBadRequest, ""},
+		{"Pz8/", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/base64/x", nil)
+			req.SetPathValue("value", tt.value)
+			rec := httptest.NewRecorder()
+			HandleBase64(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBodyMiddleware(t *testing.T) {
+	const limit = 16
+	h := MaxBodyMiddleware(limit)(http.HandlerFunc(HandleEcho))
+
+	tests := []struct {
+		size int
+		want int
+	}{
+		{limit, http.StatusOK},
+		{limit + 1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", tt.size)))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%d byte body: status = %d, want %d", tt.size, rec.Code, tt.want)
+		}
+	}
+}
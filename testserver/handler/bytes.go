@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// MaxRandomBytes is the largest body HandleBytes will generate.
+const MaxRandomBytes = 10 << 20
+
+// HandleBytes replies with the number of random bytes given in the {n} path
+// segment.
+func HandleBytes(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.ParseInt(r.PathValue("n"), 10, 64)
+	if err != nil || n < 0 || n > MaxRandomBytes {
+		http.Error(w, fmt.Sprintf("invalid byte count %q: want an integer between 0 and %d", r.PathValue("n"), MaxRandomBytes), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(n, 10))
+	io.CopyN(w, rand.Reader, n)
+}
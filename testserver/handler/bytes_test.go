@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestHandleBytes(t *testing.T) {
+	for _, n := range []int{0, 1, 4096} {
+		req := httptest.NewRequest(http.MethodGet, "/bytes/"+strconv.Itoa(n), nil)
+		req.SetPathValue("n", strconv.Itoa(n))
+		rec := httptest.NewRecorder()
+		HandleBytes(rec, req)
+
+		if rec.Body.Len() != n {
+			t.Errorf("/bytes/%d: got %d bytes", n, rec.Body.Len())
+		}
+		if cl := rec.Header().Get("Content-Length"); cl != strconv.Itoa(n) {
+			t.Errorf("/bytes/%d: Content-Length = %q", n, cl)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/octet-stream" {
+			t.Errorf("/bytes/%d: Content-Type = %q", n, ct)
+		}
+	}
+}
+
+func TestHandleBytesTooLarge(t *testing.T) {
+	n := strconv.Itoa(MaxRandomBytes + 1)
+	req := httptest.NewRequest(http.MethodGet, "/bytes/"+n, nil)
+	req.SetPathValue("n", n)
+	rec := httptest.NewRecorder()
+	HandleBytes(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+var (
+	cacheBody    = []byte("This response is cacheable.\n")
+	cacheETag    = `"` + etagOf(cacheBody) + `"`
+	cacheModTime = time.Now().UTC().Truncate(time.Second)
+)
+
+// HandleCache replies with a fixed body carrying an ETag and Last-Modified,
+// and with 304 Not Modified when If-None-Match or If-Modified-Since show the
+// client already has it.
+func HandleCache(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("ETag", cacheETag)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	// ServeContent evaluates the conditional headers against the ETag set
+	// above and cacheModTime.
+	http.ServeContent(w, r, "", cacheModTime, bytes.NewReader(cacheBody))
+}
+
+func etagOf(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleCache(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HandleCache(rec, httptest.NewRequest(http.MethodGet, "/cache", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != string(cacheBody) {
+		t.Fatalf("first request = %d %q, want 200 with the body", rec.Code, rec.Body)
+	}
+	etag := rec.Header().Get("ETag")
+	lastModified := rec.Header().Get("Last-Modified")
+	if etag == "" || lastModified == "" {
+		t.Fatalf("ETag %q, Last-Modified %q, want both set", etag, lastModified)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{"matching etag", "If-None-Match", etag, http.StatusNotModified},
+		{"stale etag", "If-None-Match", `"other"`, http.StatusOK},
+		{"modified since", "If-Modified-Since", lastModified, http.StatusNotModified},
+		{"older copy", "If-Modified-Since", cacheModTime.Add(-time.Hour).Format(http.TimeFormat), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/cache", nil)
+			req.Header.Set(tt.header, tt.value)
+			rec := httptest.NewRecorder()
+			HandleCache(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 carried a body: %q", rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// cacheDirectives are the Cache-Control directives HandleCacheControl
+// accepts, in the order they are written. Only max-age takes a value.
+var cacheDirectives = []string{"public", "private", "no-cache", "no-store", "max-age"}
+
+// HandleCacheControl sets Cache-Control from the query parameters, e.g.
+// /cache-control?max-age=60&public, and replies with a short body naming the
+// header it sent. Unknown directives, values on flag directives, a max-age
+// that is not a non-negative integer and public together with private are
+// rejected with 400.
+func HandleCacheControl(w http.ResponseWriter, r *http.Request) {
+	value, err := cacheControl(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Cache-Control", value)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "Cache-Control: %s\n", value)
+}
+
+func cacheControl(query url.Values) (string, error) {
+	if len(query) == 0 {
+		return "", fmt.Errorf("no directives given: want some of %s", strings.Join(cacheDirectives, ", "))
+	}
+	for name, values := range query {
+		if !slices.Contains(cacheDirectives, name) {
+			return "", fmt.Errorf("unknown directive %q: want one of %s", name, strings.Join(cacheDirectives, ", "))
+		}
+		if len(values) > 1 {
+			return "", fmt.Errorf("directive %q given more than once", name)
+		}
+	}
+	if _, ok := query["public"]; ok {
+		if _, ok := query["private"]; ok {
+			return "", errors.New("directives public and private cannot be combined")
+		}
+	}
+
+	var parts []string
+	for _, name := range cacheDirectives {
+		values, ok := query[name]
+		if !ok {
+			continue
+		}
+		if name != "max-age" {
+			if values[0] != "" {
+				return "", fmt.Errorf("invalid %s %q: the directive takes no value", name, values[0])
+			}
+			parts = append(parts, name)
+			continue
+		}
+		secs, err := strconv.Atoi(values[0])
+		if err != nil || secs < 0 {
+			return "", fmt.Errorf("invalid max-age %q: want a non-negative integer", values[0])
+		}
+		parts = append(parts, "max-age="+strconv.Itoa(secs))
+	}
+	return strings.Join(parts, ", "), nil
+}
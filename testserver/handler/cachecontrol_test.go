@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleCacheControl(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"max-age=60&public", "public, max-age=60"},
+		{"private&no-cache&max-age=0", "private, no-cache, max-age=0"},
+		{"no-store", "no-store"},
+		{"no-store=&no-cache", "no-cache, no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HandleCacheControl(rec, httptest.NewRequest(http.MethodGet, "/cache-control?"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleCacheControlInvalid(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"max-age=foo",
+		"max-age=-1",
+		"max-age",
+		"public=yes",
+		"public&private",
+		"immutable",
+		"max-age=1&max-age=2",
+	} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HandleCacheControl(rec, httptest.NewRequest(http.MethodGet, "/cache-control?"+query, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+			if rec.Header().Get("Cache-Control") != "" {
+				t.Error("Cache-Control set on a rejected request")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxCapturedBody is how much of each request body a Capture keeps.
+const MaxCapturedBody = 64 << 10
+
+// defaultLastRequests is how many requests /debug/last returns without n.
+const defaultLastRequests = 10
+
+// CapturedRequest is what a Capture records about one request.
+type CapturedRequest struct {
+	Time      time.Time   `json:"time"`
+	RequestID string      `json:"request_id"`
+	Method    string      `json:"method"`
+	Path      string      `json:"path"`
+	Headers   http.Header `json:"headers"`
+	Body      string      `json:"body"`
+	// BodyEncoding is "base64" when Body had to be encoded because the raw
+	// body was not valid UTF-8, and "utf-8" otherwise.
+	BodyEncoding string `json:"body_encoding"`
+	// BodyTruncated is set when the handler read more than
+	// MaxCapturedBody bytes and only that much was kept.
+	BodyTruncated bool `json:"body_truncated"`
+}
+
+// Capture keeps the most recent requests in a fixed-size ring buffer. It is
+// safe for concurrent use.
+type Capture struct {
+	mu   sync.Mutex
+	ring []CapturedRequest
+	next int
+	full bool
+}
+
+// NewCapture returns a Capture holding the last size requests. size must be
+// positive.
+func NewCapture(size int) *Capture {
+	return &Capture{ring: make([]CapturedRequest, size)}
+}
+
+// Middleware records every request passing through it once next returns.
+// The body is teed as next reads it, so only what the handler consumed is
+// captured and the handler still sees the full body.
+func (c *Capture) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := &captureBody{ReadCloser: r.Body}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+		entry := CapturedRequest{
+			Time:      time.Now().UTC(),
+			RequestID: RequestIDFromContext(r.Context()),
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Headers:   r.Header.Clone(),
+		}
+		defer func() {
+			entry.Body, entry.BodyEncoding = encodeBody(body.buf.Bytes())
+			entry.BodyTruncated = body.truncated
+			c.add(entry)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *Capture) add(entry CapturedRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ring[c.next] = entry
+	c.next = (c.next + 1) % len(c.ring)
+	if c.next == 0 {
+		c.full = true
+	}
+}
+
+// Last returns up to n captured requests, newest first.
+func (c *Capture) Last(n int) []CapturedRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := c.next
+	if c.full {
+		count = len(c.ring)
+	}
+	n = min(n, count)
+	out := make([]CapturedRequest, 0, n)
+	for i := range n {
+		out = append(out, c.ring[(c.next-1-i+len(c.ring))%len(c.ring)])
+	}
+	return out
+}
+
+// ServeHTTP replies with the last n captured requests as a JSON array,
+// newest first, where n comes from the n query parameter (default 10).
+func (c *Capture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := defaultLastRequests
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n < 1 || n > len(c.ring) {
+			http.Error(w, fmt.Sprintf("invalid n %q: want an integer between 1 and %d", v, len(c.ring)), http.StatusBadRequest)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c.Last(n))
+}
+
+// captureBody copies up to MaxCapturedBody bytes of what is read through it.
+type captureBody struct {
+	io.ReadCloser
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	keep := min(n, MaxCapturedBody-b.buf.Len())
+	b.buf.Write(p[:keep])
+	if keep < n {
+		b.truncated = true
+	}
+	return n, err
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	c := NewCapture(2)
+	var handlerSaw string
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handlerSaw = string(body)
+	}))
+
+	for _, body := range []string{"first", "second", "third"} {
+		req := httptest.NewRequest(http.MethodPost, "/"+body, strings.NewReader(body))
+		req.Header.Set("X-Call", body)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if handlerSaw != body {
+			t.Errorf("handler read %q, want %q", handlerSaw, body)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/last", nil))
+	var got []CapturedRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	// The ring holds two, so the oldest request has been dropped.
+	want := []string{"third", "second"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(got), len(want), got)
+	}
+	for i, name := range want {
+		if got[i].Method != http.MethodPost || got[i].Path != "/"+name || got[i].Body != name || got[i].Headers.Get("X-Call") != name {
+			t.Errorf("request %d = %+v, want POST /%s", i, got[i], name)
+		}
+	}
+}
+
+func TestCaptureNewestFirst(t *testing.T) {
+	c := NewCapture(10)
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, path := range []string{"/a", "/b", "/c"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/last?n=10", nil))
+	var got []CapturedRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, req := range got {
+		paths = append(paths, req.Path)
+	}
+	if strings.Join(paths, ",") != "/c,/b,/a" {
+		t.Errorf("paths = %q, want newest first", paths)
+	}
+
+	for _, n := range []string{"0", "11", "many"} {
+		rec := httptest.NewRecorder()
+		c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/last?n="+n, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("n=%s: status = %d, want 400", n, rec.Code)
+		}
+	}
+}
+
+func TestCaptureTruncatesBody(t *testing.T) {
+	c := NewCapture(1)
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", MaxCapturedBody+1))))
+
+	got := c.Last(1)
+	if len(got) != 1 || len(got[0].Body) != MaxCapturedBody || !got[0].BodyTruncated {
+		t.Errorf("captured %d bytes, truncated %v; want %d bytes, truncated", len(got[0].Body), got[0].BodyTruncated, MaxCapturedBody)
+	}
+}
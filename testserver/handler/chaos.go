@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"sync"
+)
+
+// ChaosMiddleware fails roughly rate (0 to 1) of requests with a 500 before
+// they reach next, marking them with "X-Chaos: injected". Decisions come from
+// an RNG seeded with seed, so a fixed seed fails the same sequence of
+// requests every run.
+func ChaosMiddleware(rate float64, seed uint64) func(http.Handler) http.Handler {
+	var (
+		mu  sync.Mutex
+		rng = rand.New(rand.NewPCG(seed, seed))
+	)
+	fail := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return rng.Float64() < rate
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fail() {
+				w.Header().Set("X-Chaos", "injected")
+				http.Error(w, "injected failure", http.StatusInternalServerError)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestChaosMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		rate     float64
+		wantFail bool
+	}{
+		{1, true},
+		{0, false},
+	}
+	for _, tt := range tests {
+		h := ChaosMiddleware(tt.rate, 1)(ok)
+		for i := range 50 {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			failed := rec.Code == http.StatusInternalServerError
+			if failed != tt.wantFail {
+				t.Fatalf("rate %g, request %d: status %d", tt.rate, i, rec.Code)
+			}
+			if failed != (rec.Header().Get("X-Chaos") == "injected") {
+				t.Fatalf("rate %g, request %d: X-Chaos = %q", tt.rate, i, rec.Header().Get("X-Chaos"))
+			}
+		}
+	}
+}
+
+func TestChaosMiddlewareSeeded(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	run := func() []int {
+		h := ChaosMiddleware(0.5, 42)(ok)
+		var codes []int
+		for range 20 {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			codes = append(codes, rec.Code)
+		}
+		return codes
+	}
+	first := run()
+	if !slices.Equal(first, run()) {
+		t.Error("the same seed failed different requests")
+	}
+	if !slices.Contains(first, http.StatusOK) || !slices.Contains(first, http.StatusInternalServerError) {
+		t.Errorf("codes = %v, want a mix at rate 0.5", first)
+	}
+}
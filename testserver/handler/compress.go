@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// encoder is the part of a compressing writer CompressMiddleware relies on.
+type encoder interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
+// encodings lists the supported content codings in the order preferred when
+// a client rates several of them equally.
+var encodings = []struct {
+	name string
+	new  func(io.Writer) encoder
+}{
+	{"br", func(w io.Writer) encoder { return brotli.NewWriter(w) }},
+	{"gzip", func(w io.Writer) encoder { return gzip.NewWriter(w) }},
+	{"deflate", func(w io.Writer) encoder { return zlib.NewWriter(w) }},
+}
+
+// CompressMiddleware compresses response bodies with br, gzip or deflate,
+// whichever the client's Accept-Encoding rates highest. Clients that accept
+// none of them, HEAD requests, protocol upgrades such as WebSocket
+// handshakes, and responses with a set Content-Length or an
+// application/octet-stream body get the response untouched.
+func CompressMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		coding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" || coding == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cw := &compressResponseWriter{ResponseWriter: w, coding: coding}
+		// Finish the stream only when next returns normally. Closing while a
+		// panic unwinds would commit an implicit 200 and leave nothing for
+		// RecoverMiddleware to report.
+		completed := false
+		defer func() {
+			if !completed {
+				cw.abandon()
+			}
+		}()
+		next.ServeHTTP(cw, r)
+		completed = true
+		cw.close()
+	})
+}
+
+// negotiateEncoding picks the supported coding an Accept-Encoding value rates
+// highest, or "" when the client prefers identity or accepts none of them.
+// A "*" entry rates every coding not listed by name, and q=0 refuses one.
+func negotiateEncoding(header string) string {
+	q := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "*" {
+			wildcard = qvalue(params)
+		} else if coding != "" {
+			q[coding] = qvalue(params)
+		}
+	}
+
+	best, bestQ := "", 0.0
+	if identity, ok := q["identity"]; ok {
+		bestQ = identity
+	}
+	for _, enc := range encodings {
+		encQ, ok := q[enc.name]
+		if !ok {
+			encQ = wildcard
+		}
+		if encQ > bestQ {
+			best, bestQ = enc.name, encQ
+		}
+	}
+	return best
+}
+
+// qvalue returns the q parameter of an Accept-Encoding entry, defaulting to 1
+// when none is given.
+func qvalue(params string) float64 {
+	for _, p := range strings.Split(params, ";") {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(k), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
+
+// compressResponseWriter compresses everything written through it with
+// coding once the handler commits to a status that carries a body. The status
+// line is held back until the first body bytes arrive so Content-Type can
+// still be sniffed from the uncompressed data; net/http no longer sniffs once
+// Content-Encoding is set.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	coding    string
+	enc       encoder
+	code      int
+	committed bool
+	compress  bool
+}
+
+func (w *compressResponseWriter) WriteHeader(code int) {
+	if w.code != 0 {
+		return
+	}
+	w.code = code
+	h := w.Header()
+	if bodyAllowed(code) && h.Get("Content-Encoding") == "" && !exactLength(h) {
+		w.compress = true
+		h.Set("Content-Encoding", w.coding)
+		h.Del("Content-Length")
+	}
+}
+
+// commit sends the status line and headers if that has not happened yet.
+func (w *compressResponseWriter) commit() {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.committed {
+		w.committed = true
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+}
+
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.compress {
+		w.commit()
+		return w.ResponseWriter.Write(p)
+	}
+	if !w.committed {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.commit()
+	}
+	return w.encoder().Write(p)
+}
+
+func (w *compressResponseWriter) Flush() {
+	w.commit()
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressResponseWriter) encoder() encoder {
+	if w.enc == nil {
+		for _, enc := range encodings {
+			if enc.name == w.coding {
+				w.enc = enc.new(w.ResponseWriter)
+			}
+		}
+	}
+	return w.enc
+}
+
+// close finishes the compressed stream. A compressed response with an empty
+// body still gets a valid (empty) stream so clients can decode it.
+func (w *compressResponseWriter) close() {
+	w.commit()
+	if !w.compress {
+		return
+	}
+	w.encoder().Close()
+}
+
+// abandon undoes the Content-Encoding header if the status line has not gone
+// out yet, so whoever answers in place of the failed handler sends an
+// uncompressed response.
+func (w *compressResponseWriter) abandon() {
+	if w.compress && !w.committed {
+		w.Header().Del("Content-Encoding")
+	}
+}
+
+// exactLength reports whether a response promises its exact size, either by
+// setting Content-Length or by being opaque binary data such as /bytes or
+// /drip produce. Such responses are passed through uncompressed so clients
+// see the length the handler chose.
+func exactLength(h http.Header) bool {
+	return h.Get("Content-Length") != "" || h.Get("Content-Type") == "application/octet-stream"
+}
+
+// bodyAllowed reports whether a response with the given status may carry a
+// body.
+func bodyAllowed(code int) bool {
+	return code >= 200 && code != http.StatusNoContent && code != http.StatusNotModified
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestCompressMiddleware(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(HandleEcho))
+	tests := []struct {
+		acceptEncoding, want string
+		decode               func(io.Reader) (io.Reader, error)
+	}{
+		{"gzip", "gzip", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"br;q=1.0, gzip;q=0.5", "br", func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+		{"deflate, gzip;q=0.9", "deflate", func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }},
+		{"gzip, br", "br", func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("squeeze me"))
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if ce := rec.Header().Get("Content-Encoding"); ce != tt.want {
+				t.Fatalf("Content-Encoding = %q, want %q", ce, tt.want)
+			}
+			if vary := rec.Header().Get("Vary"); vary != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", vary)
+			}
+			zr, err := tt.decode(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(zr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(body), "You sent: squeeze me") {
+				t.Errorf("decompressed body = %q, want the echoed request body", body)
+			}
+		})
+	}
+}
+
+func TestCompressMiddlewareNotRequested(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(HandleEcho))
+	for _, ae := range []string{"", "identity", "gzip;q=0", "compress", "gzip;q=0.5, identity"} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("plain"))
+		req.Header.Set("Accept-Encoding", ae)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want none", ae, ce)
+		}
+		if !strings.Contains(rec.Body.String(), "You sent: plain") {
+			t.Errorf("Accept-Encoding %q: body = %q, want it uncompressed", ae, rec.Body)
+		}
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header, want string
+	}{
+		{"", ""},
+		{"*", "br"},
+		{"*;q=0.5, br;q=0", "gzip"},
+		{"DEFLATE", "deflate"},
+		{"gzip;q=0.2, deflate;q=0.8", "deflate"},
+		{"identity;q=0, gzip;q=0.1", "gzip"},
+		{"gzip;q=bogus", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCompressMiddlewareSniffsContentType(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("no type set by the handler"))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if ce := rec.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", ce)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want it sniffed from the uncompressed body", ct)
+	}
+}
+
+func TestCompressMiddlewareHead(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(HandleCache))
+	req := httptest.NewRequest(http.MethodHead, "/cache", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("Content-Encoding = %q, want none for HEAD", ce)
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != strconv.Itoa(len(cacheBody)) {
+		t.Errorf("Content-Length = %q, want the identity length %d", cl, len(cacheBody))
+	}
+}
+
+func TestCompressMiddlewareKeepsExactLength(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(HandleBytes))
+	req := httptest.NewRequest(http.MethodGet, "/bytes/64", nil)
+	req.SetPathValue("n", "64")
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("Content-Encoding = %q, want none", ce)
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != "64" {
+		t.Errorf("Content-Length = %q, want 64", cl)
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HandleCookies replies with the cookies sent by the client as a JSON object
+// of name to value.
+func HandleCookies(w http.ResponseWriter, r *http.Request) {
+	cookies := make(map[string]string)
+	for _, c := range r.Cookies() {
+		cookies[c.Name] = c.Value
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]map[string]string{"cookies": cookies})
+}
+
+// HandleSetCookies sets a cookie for every query parameter, so
+// /cookies/set?a=1&b=2 sets a=1 and b=2, then redirects to /cookies.
+func HandleSetCookies(w http.ResponseWriter, r *http.Request) {
+	for name, values := range r.URL.Query() {
+		http.SetCookie(w, &http.Cookie{Name: name, Value: values[len(values)-1], Path: "/"})
+	}
+	http.Redirect(w, r, "/cookies", http.StatusFound)
+}
+
+// HandleDeleteCookies expires the cookies named by the query parameters, so
+// /cookies/delete?a&b removes a and b, then redirects to /cookies.
+func HandleDeleteCookies(w http.ResponseWriter, r *http.Request) {
+	for name := range r.URL.Query() {
+		http.SetCookie(w, &http.Cookie{Name: name, Path: "/", MaxAge: -1})
+	}
+	http.Redirect(w, r, "/cookies", http.StatusFound)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCookies(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HandleSetCookies(rec, httptest.NewRequest(http.MethodGet, "/cookies/set?flavor=oatmeal", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/cookies" {
+		t.Fatalf("set = %d to %q, want 302 to /cookies", rec.Code, rec.Header().Get("Location"))
+	}
+	set := rec.Result().Cookies()
+	if len(set) != 1 || set[0].Name != "flavor" || set[0].Value != "oatmeal" {
+		t.Fatalf("Set-Cookie = %v, want flavor=oatmeal", set)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/cookies", nil)
+	req.AddCookie(set[0])
+	rec = httptest.NewRecorder()
+	HandleCookies(rec, req)
+	var got struct{ Cookies map[string]string }
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Cookies["flavor"] != "oatmeal" {
+		t.Errorf("cookies = %v, want flavor=oatmeal", got.Cookies)
+	}
+
+	rec = httptest.NewRecorder()
+	HandleDeleteCookies(rec, httptest.NewRequest(http.MethodGet, "/cookies/delete?flavor", nil))
+	deleted := rec.Result().Cookies()
+	if len(deleted) != 1 || deleted[0].Name != "flavor" || deleted[0].MaxAge >= 0 {
+		t.Errorf("Set-Cookie = %v, want flavor expired with a negative Max-Age", deleted)
+	}
+}
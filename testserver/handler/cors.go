@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+)
+
+const corsAllowMethods = "GET, HEAD, POST, PUT, PATCH, DELETE, OPTIONS"
+
+// CORSMiddleware adds CORS headers for requests whose Origin is in origins.
+// An entry of "*" allows any origin. Preflight OPTIONS requests are answered
+// with 204 directly; requests from other origins get no CORS headers at all.
+func CORSMiddleware(origins []string) func(http.Handler) http.Handler {
+	wildcard := slices.Contains(origins, "*")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			allowed := origin != "" && (wildcard || slices.Contains(origins, origin))
+			h := w.Header()
+			if allowed {
+				if wildcard {
+					h.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					h.Set("Access-Control-Allow-Origin", origin)
+					h.Add("Vary", "Origin")
+				}
+			}
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if allowed {
+				h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+				if reqHeaders := r.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+					h.Set("Access-Control-Allow-Headers", reqHeaders)
+				}
+				h.Set("Access-Control-Max-Age", "600")
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
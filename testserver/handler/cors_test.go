@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	h := CORSMiddleware([]string{"https://allowed.test"})(http.HandlerFunc(HandleEcho))
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		preflight  bool
+		wantStatus int
+		wantOrigin string
+	}{
+		{"matching origin", http.MethodGet, "https://allowed.test", false, http.StatusOK, "https://allowed.test"},
+		{"other origin", http.MethodGet, "https://evil.test", false, http.StatusOK, ""},
+		{"preflight", http.MethodOptions, "https://allowed.test", true, http.StatusNoContent, "https://allowed.test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+				req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if tt.preflight {
+				if got := rec.Header().Get("Access-Control-Allow-Methods"); got != corsAllowMethods {
+					t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, corsAllowMethods)
+				}
+				if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "X-Custom" {
+					t.Errorf("Access-Control-Allow-Headers = %q, want X-Custom", got)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareWildcard(t *testing.T) {
+	h := CORSMiddleware([]string{"*"})(http.HandlerFunc(HandleEcho))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://anyone.test")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
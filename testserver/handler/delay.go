@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MaxDelay is the longest delay HandleDelay will honor.
+const MaxDelay = 30 * time.Second
+
+// HandleDelay waits for the duration in the {seconds} path segment before
+// replying. The segment is either whole seconds ("2") or a Go duration
+// ("1500ms"). The wait is abandoned as soon as the client goes away.
+func HandleDelay(w http.ResponseWriter, r *http.Request) {
+	d, err := parseDelay("delay", r.PathValue("seconds"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-r.Context().Done():
+		return
+	}
+	fmt.Fprintf(w, "Delayed %s\n", d)
+}
+
+// parseDelay reads s as whole seconds or a Go duration between 0 and
+// MaxDelay. name identifies the value in error messages.
+func parseDelay(name, s string) (time.Duration, error) {
+	var d time.Duration
+	if n, err := strconv.Atoi(s); err == nil {
+		d = time.Duration(n) * time.Second
+	} else if d, err = time.ParseDuration(s); err != nil {
+		return 0, fmt.Errorf("invalid %s %q: want whole seconds or a duration like 1500ms", name, s)
+	}
+	if d < 0 || d > MaxDelay {
+		return 0, fmt.Errorf("invalid %s %q: must be between 0 and %s", name, s, MaxDelay)
+	}
+	return d, nil
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleDelay(t *testing.T) {
+	tests := []struct {
+		seconds string
+		want    int
+	}{
+		{"0", http.StatusOK},
+		{"20ms", http.StatusOK},
+		{"31", http.StatusBadRequest},
+		{"-1s", http.StatusBadRequest},
+		{"soon", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.seconds, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HandleDelay(rec, delayRequest(context.Background(), tt.seconds))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleDelayCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	HandleDelay(rec, delayRequest(ctx, "10"))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("handler ran for %s after the client went away", elapsed)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want nothing written for a canceled request", rec.Body)
+	}
+}
+
+func delayRequest(ctx context.Context, seconds string) *http.Request {
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/delay/"+seconds, nil)
+	req.SetPathValue("seconds", seconds)
+	return req
+}
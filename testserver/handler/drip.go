@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MaxDripBytes is the most bytes HandleDrip will trickle out.
+const MaxDripBytes = 10 << 10
+
+// Defaults for the /drip query parameters.
+const (
+	defaultDripDuration = 2 * time.Second
+	defaultDripBytes    = 10
+)
+
+// HandleDrip waits for the optional delay query parameter, then writes
+// numbytes bytes spread evenly over duration, flushing after each one. Both
+// durations take whole seconds or a Go duration and together may not exceed
+// MaxDelay. It stops early once the client goes away.
+func HandleDrip(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	duration, delay := defaultDripDuration, time.Duration(0)
+	var err error
+	if v := q.Get("duration"); v != "" {
+		if duration, err = parseDelay("duration", v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("delay"); v != "" {
+		if delay, err = parseDelay("delay", v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if duration+delay > MaxDelay {
+		http.Error(w, fmt.Sprintf("invalid drip: delay %s plus duration %s exceeds %s", delay, duration, MaxDelay), http.StatusBadRequest)
+		return
+	}
+	n := defaultDripBytes
+	if v := q.Get("numbytes"); v != "" {
+		if n, err = strconv.Atoi(v); err != nil || n < 0 || n > MaxDripBytes {
+			http.Error(w, fmt.Sprintf("invalid numbytes %q: want an integer between 0 and %d", v, MaxDripBytes), http.StatusBadRequest)
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported by this connection", http.StatusInternalServerError)
+		return
+	}
+
+	if !sleep(r, delay) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(n))
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	if n == 0 {
+		return
+	}
+	interval := duration / time.Duration(n)
+	for range n {
+		if !sleep(r, interval) {
+			return
+		}
+		if _, err := w.Write([]byte{'*'}); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// sleep waits for d and reports whether the request is still live.
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return r.Context().Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleDrip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(HandleDrip))
+	defer srv.Close()
+
+	start := time.Now()
+	resp, err := http.Get(srv.URL + "/drip?duration=200ms&numbytes=5&delay=50ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("response took %s, want at least the delay plus duration of 250ms", elapsed)
+	}
+	if string(body) != strings.Repeat("*", 5) {
+		t.Errorf("body = %q, want 5 bytes", body)
+	}
+}
+
+func TestHandleDripInvalid(t *testing.T) {
+	for _, query := range []string{
+		"duration=soon",
+		"delay=-1s",
+		"numbytes=-1",
+		"numbytes=100000",
+		"duration=20s&delay=20s",
+	} {
+		t.Run(query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HandleDrip(rec, httptest.NewRequest(http.MethodGet, "/drip?"+query, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandleDripCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	HandleDrip(rec, httptest.NewRequestWithContext(ctx, http.MethodGet, "/drip?duration=10s", nil))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("handler ran for %s after the client went away", elapsed)
+	}
+	if rec.Body.Len() == defaultDripBytes {
+		t.Error("the whole body was written for a canceled request")
+	}
+}
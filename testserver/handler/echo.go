@@ -0,0 +1,29 @@
+// Package handler contains the HTTP handlers served by the test server.
+package handler
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HandleEcho replies with a greeting, the request line as received and the
+// request body. Only GET, POST and PUT are accepted.
+func HandleEcho(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut:
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	all, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	s := string(all)
+	if s == "" {
+		s = "<empty>"
+	}
+	fmt.Fprintf(w, "Hello from Go HTTP Server!\n%s %s\nYou sent: %s", r.Method, r.URL.RequestURI(), s)
+}
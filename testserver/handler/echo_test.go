@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleEcho(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   string
+	}{
+		{"body", http.MethodPost, "/", "ping pong", "Hello from Go HTTP Server!\nPOST /\nYou sent: ping pong"},
+		{"empty", http.MethodPost, "/", "", "Hello from Go HTTP Server!\nPOST /\nYou sent: <empty>"},
+		{"query", http.MethodGet, "/foo?x=1&q=a%20b+c", "", "Hello from Go HTTP Server!\nGET /foo?x=1&q=a%20b+c\nYou sent: <empty>"},
+		{"post path", http.MethodPost, "/foo?x=1", "hi", "Hello from Go HTTP Server!\nPOST /foo?x=1\nYou sent: hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			HandleEcho(rec, req)
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleEchoMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HandleEcho(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET, POST, PUT" {
+		t.Errorf("Allow = %q, want %q", allow, "GET, POST, PUT")
+	}
+
+	rec = httptest.NewRecorder()
+	HandleEcho(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("still here")))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "You sent: still here") {
+		t.Errorf("POST = %d %q, want 200 echoing the body", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// encodingSample is long and repetitive enough to compress well.
+var encodingSample = strings.Repeat("friend compresses this line so clients can check how it was encoded.\n", 20)
+
+// HandleEncoding replies with a plain-text body that names the coding
+// negotiated from the request's Accept-Encoding. CompressMiddleware applies
+// that coding to the response.
+func HandleEncoding(w http.ResponseWriter, r *http.Request) {
+	coding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	if coding == "" {
+		coding = "identity"
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "Negotiated encoding: %s\n%s", coding, encodingSample)
+}
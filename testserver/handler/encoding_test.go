@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestHandleEncoding(t *testing.T) {
+	h := CompressMiddleware(http.HandlerFunc(HandleEncoding))
+
+	req := httptest.NewRequest(http.MethodGet, "/encoding", nil)
+	req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.5")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if ce := rec.Header().Get("Content-Encoding"); ce != "br" {
+		t.Fatalf("Content-Encoding = %q, want br", ce)
+	}
+	body, err := io.ReadAll(brotli.NewReader(rec.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(body), "Negotiated encoding: br\n") {
+		t.Errorf("body = %q, want it to name br", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/encoding", nil)
+	req.Header.Set("Accept-Encoding", "identity")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("Content-Encoding = %q, want none", ce)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "Negotiated encoding: identity\n") {
+		t.Errorf("body = %q, want plain text naming identity", rec.Body)
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// maxFormMemory is how much of a multipart form is kept in memory; larger
+// files spill to temporary files. The overall size is still bounded by
+// MaxBodyMiddleware.
+const maxFormMemory = 32 << 20
+
+type formFile struct {
+	Field       string `json:"field"`
+	Filename    string `json:"filename"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+}
+
+type formDump struct {
+	Fields map[string][]string `json:"fields"`
+	Files  []formFile          `json:"files"`
+}
+
+// HandleForm parses a multipart/form-data POST and replies with its text
+// fields and a summary of each uploaded file.
+func HandleForm(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseMultipartForm(maxFormMemory); err != nil {
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxErr):
+			http.Error(w, fmt.Sprintf("request body exceeds %d bytes", maxErr.Limit), http.StatusRequestEntityTooLarge)
+		case errors.Is(err, http.ErrNotMultipart):
+			http.Error(w, "expected a multipart/form-data body", http.StatusBadRequest)
+		default:
+			http.Error(w, "invalid multipart form: "+err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	dump := formDump{Fields: r.MultipartForm.Value, Files: []formFile{}}
+	for field, headers := range r.MultipartForm.File {
+		for _, fh := range headers {
+			dump.Files = append(dump.Files, formFile{
+				Field:       field,
+				Filename:    fh.Filename,
+				Size:        fh.Size,
+				ContentType: fh.Header.Get("Content-Type"),
+			})
+		}
+	}
+	slices.SortStableFunc(dump.Files, func(a, b formFile) int { return cmp.Compare(a.Field, b.Field) })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dump)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleForm(t *testing.T) {
+	body, contentType := multipartBody(t, "hello, upload")
+	req := httptest.NewRequest(http.MethodPost, "/form", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	HandleForm(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var got formDump
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if v := got.Fields["note"]; len(v) != 1 || v[0] != "a text field" {
+		t.Errorf("fields = %v, want note", got.Fields)
+	}
+	want := formFile{Field: "upload", Filename: "hello.txt", Size: int64(len("hello, upload")), ContentType: "application/octet-stream"}
+	if len(got.Files) != 1 || got.Files[0] != want {
+		t.Errorf("files = %+v, want [%+v]", got.Files, want)
+	}
+}
+
+func TestHandleFormTooLarge(t *testing.T) {
+	body, contentType := multipartBody(t, strings.Repeat("x", 4096))
+	req := httptest.NewRequest(http.MethodPost, "/form", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	MaxBodyMiddleware(1024)(http.HandlerFunc(HandleForm)).ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+}
+
+// multipartBody builds a form with one text field and one file holding
+// content.
+func multipartBody(t *testing.T, content string) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := mw.WriteField("note", "a text field"); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := mw.CreateFormFile("upload", "hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(content))
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf, mw.FormDataContentType()
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MaxDecodedBytes caps how large a gzipped request body may grow once
+// inflated, so a small upload cannot expand without bound.
+const MaxDecodedBytes = 10 << 20
+
+// HandleGzipDecode echoes the request body back, inflating it first when it
+// carries Content-Encoding: gzip. A corrupt gzip stream gets a 400 and any
+// other content coding a 415.
+func HandleGzipDecode(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+	switch coding := r.Header.Get("Content-Encoding"); {
+	case coding == "" || strings.EqualFold(coding, "identity"):
+	case strings.EqualFold(coding, "gzip"):
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			decodeError(w, err)
+			return
+		}
+		defer zr.Close()
+		body = http.MaxBytesReader(w, zr, MaxDecodedBytes)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported Content-Encoding %q", coding), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	decoded, err := io.ReadAll(body)
+	if err != nil {
+		decodeError(w, err)
+		return
+	}
+	w.Write(decoded)
+}
+
+// decodeError replies 413 when a body limit was hit and 400 when the gzip
+// stream itself is broken.
+func decodeError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		http.Error(w, fmt.Sprintf("request body exceeds %d bytes", maxErr.Limit), http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "invalid gzip body: "+err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleGzipDecode(t *testing.T) {
+	const payload = "inflate me, please"
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(payload))
+	zw.Close()
+	compressed := buf.Bytes()
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		want     int
+		wantBody string
+	}{
+		{"gzip", "gzip", compressed, http.StatusOK, payload},
+		{"plain", "", []byte(payload), http.StatusOK, payload},
+		{"corrupt header", "gzip", []byte("not gzip at all"), http.StatusBadRequest, ""},
+		{"truncated", "gzip", compressed[:len(compressed)-6], http.StatusBadRequest, ""},
+		{"unsupported", "zstd", compressed, http.StatusUnsupportedMediaType, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/gzip-decode", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			HandleGzipDecode(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (%q)", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
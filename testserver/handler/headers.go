@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HandleHeaders replies with the request headers as an indented JSON object.
+// Every header maps to an array so repeated headers keep all their values;
+// keys come out sorted because encoding/json sorts map keys.
+func HandleHeaders(w http.ResponseWriter, r *http.Request) {
+	body, err := json.MarshalIndent(r.Header, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
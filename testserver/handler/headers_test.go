@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestHandleHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/headers", nil)
+	req.Header.Add("X-Test", "one")
+	req.Header.Add("X-Test", "two")
+	rec := httptest.NewRecorder()
+	HandleHeaders(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var got map[string][]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"one", "two"}; !slices.Equal(got["X-Test"], want) {
+		t.Errorf("X-Test = %q, want %q", got["X-Test"], want)
+	}
+}
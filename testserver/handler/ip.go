@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPHandler replies with the client's address as {"origin": "..."}. With
+// trustProxy set, X-Forwarded-For and then X-Real-IP take precedence over the
+// connection's remote address.
+func IPHandler(trustProxy bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"origin": ClientIP(r, trustProxy)})
+	})
+}
+
+// ClientIP returns the address the request came from, without a port. With
+// trustProxy set, X-Forwarded-For and then X-Real-IP take precedence over the
+// connection's remote address.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			if first = strings.TrimSpace(first); first != "" {
+				return first
+			}
+		}
+		if real := strings.TrimSpace(r.Header.Get("X-Real-IP")); real != "" {
+			return real
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		headers    map[string]string
+		want       string
+	}{
+		{"direct", false, nil, "192.0.2.1"},
+		{"spoofed untrusted", false, map[string]string{"X-Forwarded-For": "203.0.113.9"}, "192.0.2.1"},
+		{"forwarded trusted", true, map[string]string{"X-Forwarded-For": "203.0.113.9, 10.0.0.1"}, "203.0.113.9"},
+		{"real ip trusted", true, map[string]string{"X-Real-IP": "198.51.100.7"}, "198.51.100.7"},
+		{"trusted without headers", true, nil, "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = "192.0.2.1:5555"
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			IPHandler(tt.trustProxy).ServeHTTP(rec, req)
+
+			var got struct{ Origin string }
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Origin != tt.want {
+				t.Errorf("origin = %q, want %q", got.Origin, tt.want)
+			}
+		})
+	}
+}
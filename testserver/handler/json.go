@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HandleJSON validates the JSON request body and echoes it back indented.
+// Malformed input gets a 400 naming the offset where decoding failed.
+func HandleJSON(w http.ResponseWriter, r *http.Request) {
+	body, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			http.Error(w, fmt.Sprintf("invalid JSON at offset %d: %v", syntaxErr.Offset, syntaxErr), http.StatusBadRequest)
+		} else {
+			http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(out, '\n'))
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		want     int
+		wantBody string
+	}{
+		{"object", `{"a":1,"b":[true,null]}`, http.StatusOK, "{\n  \"a\": 1,\n  \"b\": [\n    true,\n    null\n  ]\n}\n"},
+		{"array", `[1,"two"]`, http.StatusOK, "[\n  1,\n  \"two\"\n]\n"},
+		{"truncated", `{"a":`, http.StatusBadRequest, "invalid JSON at offset 5: unexpected end of JSON input\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HandleJSON(rec, httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", HandleEcho)
+	h := RequestIDMiddleware(LoggingMiddleware(logger, nil)(mux))
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/", http.StatusOK},
+		{"/missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("log for %s is not one JSON record: %v\n%s", tt.target, err, buf.String())
+		}
+		if entry["method"] != http.MethodGet || entry["path"] != tt.target || entry["status"] != float64(tt.want) {
+			t.Errorf("log for %s = %v, want method GET, path %s, status %d", tt.target, entry, tt.target, tt.want)
+		}
+		for _, field := range []string{"duration_ms", "bytes", "request_id"} {
+			if _, ok := entry[field]; !ok {
+				t.Errorf("log for %s is missing %q: %v", tt.target, field, entry)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the request duration
+// histogram.
+var durationBuckets = [...]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics counts handled requests and exposes them in the Prometheus text
+// exposition format. The zero value is ready to use.
+type Metrics struct {
+	requests atomic.Uint64
+	classes  [5]atomic.Uint64 // 1xx through 5xx
+	buckets  [len(durationBuckets)]atomic.Uint64
+	sumNanos atomic.Int64
+}
+
+// Observe records one request that finished with status after d.
+func (m *Metrics) Observe(status int, d time.Duration) {
+	m.requests.Add(1)
+	if class := status/100 - 1; class >= 0 && class < len(m.classes) {
+		m.classes[class].Add(1)
+	}
+	secs := d.Seconds()
+	for i, le := range durationBuckets {
+		if secs <= le {
+			m.buckets[i].Add(1)
+			break
+		}
+	}
+	m.sumNanos.Add(int64(d))
+}
+
+// ServeHTTP writes the current counters in the text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	total := m.requests.Load()
+	fmt.Fprintln(w, "# HELP http_requests_total Total number of HTTP requests handled.")
+	fmt.Fprintln(w, "# TYPE http_requests_total counter")
+	fmt.Fprintf(w, "http_requests_total %d\n", total)
+
+	fmt.Fprintln(w, "# HELP http_responses_total HTTP responses by status class.")
+	fmt.Fprintln(w, "# TYPE http_responses_total counter")
+	for i := range m.classes {
+		fmt.Fprintf(w, "http_responses_total{class=\"%dxx\"} %d\n", i+1, m.classes[i].Load())
+	}
+
+	// Buckets are stored individually and reported cumulatively; the count
+	// is the total rather than a sum of buckets so slow requests past the
+	// last bound still land in +Inf.
+	fmt.Fprintln(w, "# HELP http_request_duration_seconds Time spent handling HTTP requests.")
+	fmt.Fprintln(w, "# TYPE http_request_duration_seconds histogram")
+	var cumulative uint64
+	for i, le := range durationBuckets {
+		cumulative += m.buckets[i].Load()
+		fmt.Fprintf(w, "http_request_duration_seconds_bucket{le=\"%s\"} %d\n", strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+	}
+	fmt.Fprintf(w, "http_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", total)
+	fmt.Fprintf(w, "http_request_duration_seconds_sum %g\n", time.Duration(m.sumNanos.Load()).Seconds())
+	fmt.Fprintf(w, "http_request_duration_seconds_count %d\n", total)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", HandleEcho)
+	metrics := &Metrics{}
+	mux.Handle("/metrics", metrics)
+	h := LoggingMiddleware(discardLogger, metrics)(mux)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := scrape(t, h, "http_requests_total"); got != 1 {
+		t.Errorf("http_requests_total = %d, want 1", got)
+	}
+	// The first scrape counts as a request too.
+	if got, want := scrape(t, h, "http_requests_total"), uint64(2); got != want {
+		t.Errorf("http_requests_total = %d, want %d", got, want)
+	}
+}
+
+func TestMetricsHistogram(t *testing.T) {
+	var m Metrics
+	m.Observe(http.StatusOK, 0)
+	m.Observe(http.StatusNotFound, 0)
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		`http_responses_total{class="2xx"} 1`,
+		`http_responses_total{class="4xx"} 1`,
+		`http_request_duration_seconds_bucket{le="0.005"} 2`,
+		`http_request_duration_seconds_bucket{le="+Inf"} 2`,
+		`http_request_duration_seconds_count 2`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics output missing %q:\n%s", line, body)
+		}
+	}
+}
+
+// scrape fetches /metrics through h and returns the value of the named
+// unlabelled sample.
+func scrape(t *testing.T, h http.Handler, name string) uint64 {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		if v, ok := strings.CutPrefix(sc.Text(), name+" "); ok {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return n
+		}
+	}
+	t.Fatalf("sample %s not found in:\n%s", name, rec.Body)
+	return 0
+}
@@ -0,0 +1,15 @@
+package handler
+
+import "net/http"
+
+// MockHandler replies to every request with the same canned status, headers
+// and body.
+func MockHandler(status int, header http.Header, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMockHandler(t *testing.T) {
+	h := MockHandler(http.StatusTeapot, http.Header{"X-Mock": {"yes"}}, "short and stout")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusTeapot || rec.Body.String() != "short and stout" || rec.Header().Get("X-Mock") != "yes" {
+		t.Errorf("response = %d %v %q, want the canned reply", rec.Code, rec.Header(), rec.Body)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type notFoundBody struct {
+	Error     string   `json:"error"`
+	Path      string   `json:"path"`
+	Endpoints []string `json:"endpoints"`
+}
+
+// NotFoundHandler replies 404 with a JSON body naming the requested path and
+// the endpoints that do exist.
+func NotFoundHandler(endpoints []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(notFoundBody{Error: "not found", Path: r.URL.Path, Endpoints: endpoints})
+	})
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// ProxyHandler forwards requests to target. The client's Host header is kept
+// unless host is set, in which case it is sent instead. X-Forwarded-For from
+// the client is extended with the client's address rather than replaced.
+// Upstream failures are logged to logger and answered with 502.
+func ProxyHandler(target *url.URL, host string, logger *slog.Logger) http.Handler {
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.Host = pr.In.Host
+			if host != "" {
+				pr.Out.Host = host
+			}
+			// Rewrite strips the inbound X-Forwarded-* headers; restore
+			// X-Forwarded-For so SetXForwarded appends to the chain.
+			pr.Out.Header["X-Forwarded-For"] = pr.In.Header["X-Forwarded-For"]
+			pr.SetXForwarded()
+		},
+		ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+}
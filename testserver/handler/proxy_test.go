@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestProxyHandler(t *testing.T) {
+	var gotHost, gotFwd string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost, gotFwd = r.Host, r.Header.Get("X-Forwarded-For")
+		w.Write([]byte("upstream " + r.URL.Path))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	tests := []struct {
+		name, host, wantHost string
+	}{
+		{"preserve host", "", "friend.test"},
+		{"override host", "api.test", "api.test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://friend.test/hello", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			rec := httptest.NewRecorder()
+			ProxyHandler(target, tt.host, discardLogger).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK || rec.Body.String() != "upstream /hello" {
+				t.Errorf("response = %d %q, want the upstream reply", rec.Code, rec.Body)
+			}
+			if gotHost != tt.wantHost {
+				t.Errorf("upstream Host = %q, want %q", gotHost, tt.wantHost)
+			}
+			// httptest.NewRequest comes from 192.0.2.1.
+			if want := "203.0.113.7, 192.0.2.1"; gotFwd != want {
+				t.Errorf("X-Forwarded-For = %q, want %q", gotFwd, want)
+			}
+		})
+	}
+}
+
+func TestProxyHandlerUpstreamDown(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	target, _ := url.Parse(upstream.URL)
+	upstream.Close()
+
+	rec := httptest.NewRecorder()
+	ProxyHandler(target, "", discardLogger).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// idleLimiterTTL is how long a per-key limiter may go unused before it is
+// dropped. By then its bucket has refilled, so forgetting it changes nothing.
+const idleLimiterTTL = 3 * time.Minute
+
+// RateLimitMiddleware allows rps requests per second with bursts of up to
+// burst, replying 429 with Retry-After when the token bucket is empty. With a
+// nil key one bucket is shared by every request; otherwise each distinct
+// key(r), such as the client IP, gets its own bucket.
+func RateLimitMiddleware(rps float64, burst int, key func(*http.Request) string) func(http.Handler) http.Handler {
+	l := &limiters{limit: rate.Limit(rps), burst: burst, byKey: make(map[string]*keyedLimiter)}
+	global := rate.NewLimiter(l.limit, burst)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lim := global
+			if key != nil {
+				lim = l.get(key(r))
+			}
+			res := lim.Reserve()
+			if delay := res.Delay(); delay > 0 {
+				res.Cancel()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type keyedLimiter struct {
+	*rate.Limiter
+	lastSeen time.Time
+}
+
+// limiters hands out one limiter per key and forgets idle ones so the map
+// stays bounded by the number of recently active clients.
+type limiters struct {
+	limit     rate.Limit
+	burst     int
+	mu        sync.Mutex
+	byKey     map[string]*keyedLimiter
+	lastSweep time.Time
+}
+
+func (l *limiters) get(key string) *rate.Limiter {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > idleLimiterTTL {
+		for k, kl := range l.byKey {
+			if now.Sub(kl.lastSeen) > idleLimiterTTL {
+				delete(l.byKey, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	kl, ok := l.byKey[key]
+	if !ok {
+		kl = &keyedLimiter{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.byKey[key] = kl
+	}
+	kl.lastSeen = now
+	return kl.Limiter
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	h := RateLimitMiddleware(1, 2, nil)(http.HandlerFunc(HandleEcho))
+
+	var limited int
+	for range 5 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		switch rec.Code {
+		case http.StatusOK:
+		case http.StatusTooManyRequests:
+			limited++
+			if rec.Header().Get("Retry-After") == "" {
+				t.Error("429 without Retry-After")
+			}
+		default:
+			t.Fatalf("status = %d", rec.Code)
+		}
+	}
+	if limited != 3 {
+		t.Errorf("%d of 5 requests were limited, want 3 past the burst of 2", limited)
+	}
+}
+
+func TestRateLimitMiddlewarePerIP(t *testing.T) {
+	key := func(r *http.Request) string { return ClientIP(r, false) }
+	h := RateLimitMiddleware(1, 1, key)(http.HandlerFunc(HandleEcho))
+
+	for _, tt := range []struct {
+		addr string
+		want int
+	}{
+		{"192.0.2.1:1000", http.StatusOK},
+		{"192.0.2.1:1001", http.StatusTooManyRequests},
+		{"192.0.2.2:1000", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.addr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.addr, rec.Code, tt.want)
+		}
+	}
+}
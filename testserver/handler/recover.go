@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoverMiddleware turns a panicking handler into a 500 JSON error instead of
+// a dropped connection, logging the panic value and stack trace to logger.
+// http.ErrAbortHandler is re-panicked since it deliberately aborts the
+// response.
+func RecoverMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				logger.ErrorContext(r.Context(), "handler panic",
+					"panic", v,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"request_id", RequestIDFromContext(r.Context()),
+					"stack", string(debug.Stack()),
+				)
+				// Once the status line is out there is no way to report
+				// the failure to the client.
+				if rec.code != 0 {
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": "internal server error"})
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	mux.HandleFunc("/panic", func(http.ResponseWriter, *http.Request) { panic("deliberate") })
+	mux.HandleFunc("/{$}", HandleEcho)
+	srv := httptest.NewServer(RecoverMiddleware(slog.New(slog.NewTextHandler(&logs, nil)))(mux))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/panic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError || body["error"] != "internal server error" {
+		t.Errorf("panic response = %d %v, want 500 with a JSON error", resp.StatusCode, body)
+	}
+	if !strings.Contains(logs.String(), "deliberate") || !strings.Contains(logs.String(), "stack=") {
+		t.Errorf("log = %q, want the panic value and a stack trace", logs.String())
+	}
+
+	resp, err = http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("request after panic = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// MaxRedirects is the longest redirect chain HandleRedirect will start.
+const MaxRedirects = 20
+
+// HandleRedirect answers /redirect/{n} with a 302 to /redirect/{n-1}, and
+// /redirect/0 with a 200, so clients walk a chain of n redirects.
+func HandleRedirect(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || n < 0 || n > MaxRedirects {
+		http.Error(w, fmt.Sprintf("invalid redirect count %q: want an integer between 0 and %d", r.PathValue("n"), MaxRedirects), http.StatusBadRequest)
+		return
+	}
+	if n == 0 {
+		fmt.Fprintln(w, "Redirect chain done")
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/redirect/%d", n-1), http.StatusFound)
+}
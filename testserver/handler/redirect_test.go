@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redirect/{n}", HandleRedirect)
+
+	target := "/redirect/3"
+	for hop := 3; hop > 0; hop-- {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusFound {
+			t.Fatalf("%s: status = %d, want 302", target, rec.Code)
+		}
+		want := fmt.Sprintf("/redirect/%d", hop-1)
+		if loc := rec.Header().Get("Location"); loc != want {
+			t.Fatalf("%s: Location = %q, want %q", target, loc, want)
+		}
+		target = want
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("%s: status = %d, want 200", target, rec.Code)
+	}
+}
+
+func TestHandleRedirectInvalid(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redirect/{n}", HandleRedirect)
+	for _, n := range []string{"21", "-1", "many"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/redirect/"+n, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("/redirect/%s: status = %d, want 400", n, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// maxRequestIDLen bounds client-supplied request IDs; longer ones are
+// replaced rather than copied into logs and responses.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// RequestIDMiddleware tags each request with an ID, taken from the incoming
+// X-Request-ID header or generated when absent. The ID is stored in the
+// request context and echoed back in the X-Request-ID response header.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" || len(id) > maxRequestIDLen {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// RequestIDFromContext returns the ID RequestIDMiddleware assigned to the
+// request, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var seen string
+	h := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if seen != "abc-123" || rec.Header().Get("X-Request-ID") != "abc-123" {
+		t.Errorf("incoming ID: context %q, header %q, want abc-123", seen, rec.Header().Get("X-Request-ID"))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen == "" {
+		t.Fatal("no ID generated for a request without X-Request-ID")
+	}
+	if got := rec.Header().Get("X-Request-ID"); got != seen {
+		t.Errorf("response header %q, want generated ID %q", got, seen)
+	}
+}
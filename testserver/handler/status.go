@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// HandleStatus replies with the status code given in the {code} path segment.
+// Informational 1xx codes are rejected: net/http sends them as interim
+// responses followed by a final 200, so the client would never see them as
+// the status.
+func HandleStatus(w http.ResponseWriter, r *http.Request) {
+	code, err := strconv.Atoi(r.PathValue("code"))
+	if err != nil || code < 200 || code > 599 {
+		http.Error(w, fmt.Sprintf("invalid status code %q: want an integer between 200 and 599", r.PathValue("code")), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(code)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleStatus(t *testing.T) {
+	tests := []struct {
+		code     string
+		want     int
+		wantBody bool
+	}{
+		{"204", http.StatusNoContent, false},
+		{"500", http.StatusInternalServerError, false},
+		{"abc", http.StatusBadRequest, true},
+		{"600", http.StatusBadRequest, true},
+		{"100", http.StatusBadRequest, true},
+		{"101", http.StatusBadRequest, true},
+		{"103", http.StatusBadRequest, true},
+		{"200", http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/status/"+tt.code, nil)
+			req.SetPathValue("code", tt.code)
+			rec := httptest.NewRecorder()
+			HandleStatus(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if gotBody := rec.Body.Len() > 0; gotBody != tt.wantBody {
+				t.Errorf("body = %q, want body: %v", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
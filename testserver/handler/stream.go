@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MaxStreamLines is the most lines HandleStream will write.
+const MaxStreamLines = 100
+
+// streamInterval is the pause between lines written by HandleStream.
+var streamInterval = 100 * time.Millisecond
+
+type streamLine struct {
+	ID   int       `json:"id"`
+	Path string    `json:"path"`
+	Time time.Time `json:"time"`
+}
+
+// HandleStream writes the number of JSON lines given in the {n} path segment,
+// flushing each one and pausing between them so clients see the body arrive
+// incrementally. It stops early once the client goes away.
+func HandleStream(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || n < 0 || n > MaxStreamLines {
+		http.Error(w, fmt.Sprintf("invalid line count %q: want an integer between 0 and %d", r.PathValue("n"), MaxStreamLines), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported by this connection", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	for i := range n {
+		if i > 0 {
+			select {
+			case <-time.After(streamInterval):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if err := enc.Encode(streamLine{ID: i, Path: r.URL.Path, Time: time.Now().UTC()}); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleStream(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream/{n}", HandleStream)
+	srv := httptest.NewServer(LoggingMiddleware(discardLogger, nil)(CompressMiddleware(mux)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/stream/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	lines := 0
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var line streamLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("line %d: %v", lines, err)
+		}
+		if line.ID != lines {
+			t.Errorf("line %d has id %d", lines, line.ID)
+		}
+		lines++
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if lines != 3 {
+		t.Errorf("read %d lines, want 3", lines)
+	}
+}
+
+func TestHandleStreamWithoutFlusher(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/stream/3", nil)
+	req.SetPathValue("n", "3")
+	rec := httptest.NewRecorder()
+	HandleStream(struct{ http.ResponseWriter }{rec}, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
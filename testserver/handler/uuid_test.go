@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestHandleUUID(t *testing.T) {
+	seen := map[string]bool{}
+	for range 3 {
+		rec := httptest.NewRecorder()
+		HandleUUID(rec, httptest.NewRequest(http.MethodGet, "/uuid", nil))
+
+		var body struct{ UUID string }
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if !uuidPattern.MatchString(body.UUID) {
+			t.Errorf("uuid = %q, want a version 4 UUID", body.UUID)
+		}
+		if seen[body.UUID] {
+			t.Errorf("uuid %q repeated", body.UUID)
+		}
+		seen[body.UUID] = true
+	}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// wsMessage is one WebSocket message together with its frame type, so text
+// frames are echoed as text and binary frames as binary.
+type wsMessage struct {
+	payloadType byte
+	data        []byte
+}
+
+var wsEchoCodec = websocket.Codec{
+	Marshal: func(v any) ([]byte, byte, error) {
+		m := v.(*wsMessage)
+		return m.data, m.payloadType, nil
+	},
+	Unmarshal: func(data []byte, payloadType byte, v any) error {
+		m := v.(*wsMessage)
+		m.data, m.payloadType = data, payloadType
+		return nil
+	},
+}
+
+// wsEchoServer uses golang.org/x/net/websocket, which answers pings with
+// pongs while reading and completes the close handshake when the handler
+// returns. The handshake accepts any Origin since this is a test server.
+var wsEchoServer = websocket.Server{
+	Handshake: func(*websocket.Config, *http.Request) error { return nil },
+	Handler: func(ws *websocket.Conn) {
+		// The hijacked connection keeps the server's read and write
+		// deadlines, which would cut long-lived sessions short.
+		ws.SetDeadline(time.Time{})
+		for {
+			var m wsMessage
+			if err := wsEchoCodec.Receive(ws, &m); err != nil {
+				return
+			}
+			if err := wsEchoCodec.Send(ws, &m); err != nil {
+				return
+			}
+		}
+	},
+}
+
+// HandleWebSocket upgrades the connection to a WebSocket and echoes every
+// text or binary message back until the client closes it.
+func HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	wsEchoServer.ServeHTTP(w, r)
+}
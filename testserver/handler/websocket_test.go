@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestHandleWebSocket(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", HandleWebSocket)
+	srv := httptest.NewServer(LoggingMiddleware(discardLogger, nil)(CompressMiddleware(mux)))
+	defer srv.Close()
+
+	ws, err := websocket.Dial(strings.Replace(srv.URL, "http", "ws", 1)+"/ws", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := websocket.Message.Send(ws, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	var text string
+	if err := websocket.Message.Receive(ws, &text); err != nil {
+		t.Fatal(err)
+	}
+	if text != "hello" {
+		t.Errorf("received %q, want hello", text)
+	}
+
+	if err := websocket.Message.Send(ws, []byte{0, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	var m wsMessage
+	if err := wsEchoCodec.Receive(ws, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.payloadType != websocket.BinaryFrame || string(m.data) != "\x00\x01\x02" {
+		t.Errorf("received frame type %d %q, want binary echo", m.payloadType, m.data)
+	}
+
+	if err := ws.WriteClose(1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := websocket.Message.Receive(ws, &text); !errors.Is(err, io.EOF) {
+		t.Errorf("after close got %v, want the server's close frame", err)
+	}
+	ws.Close()
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+)
+
+// ConcurrencyMiddleware lets at most workers requests run at once. When all
+// slots are busy a request waits up to queueTimeout for one to free up; with
+// a zero queueTimeout it is turned away immediately. Either way a request
+// that does not get a slot receives 503.
+func ConcurrencyMiddleware(workers int, queueTimeout time.Duration) func(http.Handler) http.Handler {
+	sem := make(chan struct{}, workers)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !acquire(sem, r, queueTimeout) {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "server is at its concurrency limit", http.StatusServiceUnavailable)
+				return
+			}
+			// Deferred so the slot is returned even if the handler panics.
+			defer func() { <-sem }()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func acquire(sem chan struct{}, r *http.Request, queueTimeout time.Duration) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	default:
+	}
+	if queueTimeout <= 0 {
+		return false
+	}
+	timer := time.NewTimer(queueTimeout)
+	defer timer.Stop()
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
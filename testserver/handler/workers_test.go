@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyMiddlewareRejects(t *testing.T) {
+	codes := hitDelayConcurrently(t, ConcurrencyMiddleware(2, 0), 5, "300ms")
+	if codes[http.StatusOK] != 2 || codes[http.StatusServiceUnavailable] != 3 {
+		t.Errorf("status counts = %v, want 2 OK and 3 rejected", codes)
+	}
+}
+
+func TestConcurrencyMiddlewareQueues(t *testing.T) {
+	start := time.Now()
+	codes := hitDelayConcurrently(t, ConcurrencyMiddleware(1, 5*time.Second), 3, "100ms")
+	if codes[http.StatusOK] != 3 {
+		t.Errorf("status counts = %v, want all 3 OK after queueing", codes)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("3 queued requests on 1 worker took %s, want them to run one at a time", elapsed)
+	}
+}
+
+func TestConcurrencyMiddlewareReleasesOnPanic(t *testing.T) {
+	h := ConcurrencyMiddleware(1, 0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/boom" {
+			panic("boom")
+		}
+	}))
+	func() {
+		defer func() { recover() }()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+	}()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status after a panic = %d, want the slot to be free again", rec.Code)
+	}
+}
+
+// hitDelayConcurrently fires n simultaneous /delay requests through mw and
+// counts the response statuses.
+func hitDelayConcurrently(t *testing.T, mw func(http.Handler) http.Handler, n int, delay string) map[int]int {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/delay/{seconds}", HandleDelay)
+	srv := httptest.NewServer(mw(mux))
+	defer srv.Close()
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		codes = make(map[int]int)
+	)
+	for range n {
+		wg.Go(func() {
+			resp, err := http.Get(srv.URL + "/delay/" + delay)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			mu.Lock()
+			codes[resp.StatusCode]++
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return codes
+}
@@ -1,24 +1,98 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"io"
-	"net/http"
+	"os"
+	"strings"
+
+	"testserver/handler"
+	"testserver/server"
 )
 
 func main() {
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		all, err := io.ReadAll(r.Body)
+	cfg, err := parseConfig(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	cfg.Logger, err = server.NewLogger(os.Stderr, cfg.LogFormat)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	srv, err := server.NewServer(cfg)
+	if err != nil {
+		cfg.Logger.Error("invalid configuration", "err", err)
+		os.Exit(2)
+	}
+	// There is no warmup, so the server is ready as soon as it is serving.
+	srv.MarkReady()
+	if err := srv.Run(context.Background()); err != nil {
+		cfg.Logger.Error("server error", "err", err)
+		os.Exit(1)
+	}
+}
+
+// parseConfig turns command-line arguments into a server.Config. Every flag
+// defaults to its FRIEND_* environment variable when set, so flags override
+// the environment and the environment overrides the built-in defaults.
+func parseConfig(args []string) (server.Config, error) {
+	var (
+		cfg        server.Config
+		cors       string
+		configFile string
+		env        envDefaults
+	)
+	fs := flag.NewFlagSet("testserver", flag.ContinueOnError)
+	fs.StringVar(&configFile, "config", envOr("FRIEND_CONFIG", ""), "JSON file whose listeners block replaces -addr, -tls-cert and -tls-key [$FRIEND_CONFIG]")
+	fs.StringVar(&cfg.Addr, "addr", envOr("FRIEND_ADDR", server.DefaultAddr), "address to listen on, as host:port or unix:/path/to.sock [$FRIEND_ADDR]")
+	fs.StringVar(&cfg.TLSCert, "tls-cert", envOr("FRIEND_TLS_CERT", ""), "TLS certificate file; serves HTTPS together with -tls-key [$FRIEND_TLS_CERT]")
+	fs.StringVar(&cfg.TLSKey, "tls-key", envOr("FRIEND_TLS_KEY", ""), "TLS private key file; serves HTTPS together with -tls-cert [$FRIEND_TLS_KEY]")
+	fs.BoolVar(&cfg.H2C, "h2c", env.bool("FRIEND_H2C", false), "accept HTTP/2 without TLS (prior knowledge) alongside HTTP/1.1 [$FRIEND_H2C]")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", env.duration("FRIEND_SHUTDOWN_TIMEOUT", server.DefaultShutdownTimeout), "how long to wait for in-flight requests on shutdown [$FRIEND_SHUTDOWN_TIMEOUT]")
+	fs.DurationVar(&cfg.ReadHeaderTimeout, "read-header-timeout", env.duration("FRIEND_READ_HEADER_TIMEOUT", server.DefaultReadHeaderTimeout), "how long a client may take to send request headers [$FRIEND_READ_HEADER_TIMEOUT]")
+	fs.DurationVar(&cfg.ReadTimeout, "read-timeout", env.duration("FRIEND_READ_TIMEOUT", server.DefaultReadTimeout), "how long a client may take to send the whole request [$FRIEND_READ_TIMEOUT]")
+	fs.DurationVar(&cfg.WriteTimeout, "write-timeout", env.duration("FRIEND_WRITE_TIMEOUT", server.DefaultWriteTimeout), "how long writing a response may take [$FRIEND_WRITE_TIMEOUT]")
+	fs.DurationVar(&cfg.IdleTimeout, "idle-timeout", env.duration("FRIEND_IDLE_TIMEOUT", server.DefaultIdleTimeout), "how long an idle keep-alive connection stays open [$FRIEND_IDLE_TIMEOUT]")
+	fs.Int64Var(&cfg.MaxBodyBytes, "max-body", env.int64("FRIEND_MAX_BODY", handler.DefaultMaxBodyBytes), "maximum request body size in bytes [$FRIEND_MAX_BODY]")
+	fs.StringVar(&cors, "cors-origins", envOr("FRIEND_CORS_ORIGINS", ""), "comma-separated origins allowed to make CORS requests, or * for any [$FRIEND_CORS_ORIGINS]")
+	fs.StringVar(&cfg.Auth, "auth", envOr("FRIEND_AUTH", ""), "require HTTP basic auth with these user:password credentials [$FRIEND_AUTH]")
+	fs.BoolVar(&cfg.TrustProxy, "trust-proxy", env.bool("FRIEND_TRUST_PROXY", false), "trust X-Forwarded-For and X-Real-IP for the client address [$FRIEND_TRUST_PROXY]")
+	fs.Float64Var(&cfg.RateLimit, "rate", env.float64("FRIEND_RATE", 0), "requests per second to allow before replying 429; 0 disables limiting [$FRIEND_RATE]")
+	fs.IntVar(&cfg.RateBurst, "burst", env.int("FRIEND_BURST", 0), "burst size for -rate (default: the rate rounded up) [$FRIEND_BURST]")
+	fs.BoolVar(&cfg.RateLimitPerIP, "rate-per-ip", env.bool("FRIEND_RATE_PER_IP", false), "apply -rate to each client IP separately [$FRIEND_RATE_PER_IP]")
+	fs.IntVar(&cfg.Workers, "workers", env.int("FRIEND_WORKERS", 0), "maximum requests handled at once; 0 means unlimited [$FRIEND_WORKERS]")
+	fs.DurationVar(&cfg.QueueTimeout, "queue-timeout", env.duration("FRIEND_QUEUE_TIMEOUT", 0), "how long a request waits for a free worker before 503; 0 rejects at once [$FRIEND_QUEUE_TIMEOUT]")
+	fs.IntVar(&cfg.CaptureSize, "capture", env.int("FRIEND_CAPTURE", 100), "how many recent requests /debug/last keeps; 0 disables capture [$FRIEND_CAPTURE]")
+	fs.Float64Var(&cfg.FailRate, "fail-rate", env.float64("FRIEND_FAIL_RATE", 0), "fraction of requests, 0 to 1, to fail with an injected 500 [$FRIEND_FAIL_RATE]")
+	fs.Int64Var(&cfg.Seed, "seed", env.int64("FRIEND_SEED", 0), "seed for -fail-rate decisions so runs repeat; 0 picks a random seed [$FRIEND_SEED]")
+	fs.StringVar(&cfg.ProxyTarget, "proxy-target", envOr("FRIEND_PROXY_TARGET", ""), "forward requests that match no built-in endpoint to this http(s) URL instead of echoing them [$FRIEND_PROXY_TARGET]")
+	fs.StringVar(&cfg.ProxyHost, "proxy-host", envOr("FRIEND_PROXY_HOST", ""), "Host header to send to -proxy-target (default: the client's) [$FRIEND_PROXY_HOST]")
+	fs.StringVar(&cfg.RoutesFile, "routes-file", envOr("FRIEND_ROUTES_FILE", ""), "JSON file of mock routes to serve alongside the built-in endpoints [$FRIEND_ROUTES_FILE]")
+	fs.StringVar(&cfg.AccessLog, "access-log", envOr("FRIEND_ACCESS_LOG", ""), "write request logs to this file instead of stderr [$FRIEND_ACCESS_LOG]")
+	fs.Int64Var(&cfg.AccessLogMaxBytes, "access-log-max-size", env.int64("FRIEND_ACCESS_LOG_MAX_SIZE", server.DefaultAccessLogMaxBytes), "size in bytes at which -access-log is rotated to a .1 file [$FRIEND_ACCESS_LOG_MAX_SIZE]")
+	fs.StringVar(&cfg.LogFormat, "log-format", envOr("FRIEND_LOG_FORMAT", server.DefaultLogFormat), "log output format: text or json [$FRIEND_LOG_FORMAT]")
+	if err := fs.Parse(args); err != nil {
+		return server.Config{}, err
+	}
+	if err := env.err(fs); err != nil {
+		return server.Config{}, err
+	}
+	if configFile != "" {
+		fc, err := loadConfigFile(configFile)
 		if err != nil {
-			fmt.Println(err)
-			return
+			return server.Config{}, err
+		}
+		cfg.Listeners = fc.Listeners
+	}
+	for _, origin := range strings.Split(cors, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.CORSOrigins = append(cfg.CORSOrigins, origin)
 		}
-		s := string(all)
-		fmt.Fprintf(w, "Hello from Go HTTP Server!", s)
-	})
-
-	fmt.Println("Server starting on http://localhost:8888")
-	if err := http.ListenAndServe(":8888", nil); err != nil {
-		fmt.Printf("Server error: %v\n", err)
 	}
+	return cfg, nil
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigFromEnv(t *testing.T) {
+	t.Setenv("FRIEND_ADDR", "127.0.0.1:9000")
+	t.Setenv("FRIEND_MAX_BODY", "2048")
+	t.Setenv("FRIEND_AUTH", "alice:s3cret")
+	t.Setenv("FRIEND_READ_TIMEOUT", "7s")
+	t.Setenv("FRIEND_TRUST_PROXY", "true")
+	t.Setenv("FRIEND_CORS_ORIGINS", "https://a.test, https://b.test")
+
+	cfg, err := parseConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != "127.0.0.1:9000" || cfg.MaxBodyBytes != 2048 || cfg.Auth != "alice:s3cret" ||
+		cfg.ReadTimeout != 7*time.Second || !cfg.TrustProxy {
+		t.Errorf("config from env = %+v", cfg)
+	}
+	if want := []string{"https://a.test", "https://b.test"}; !slices.Equal(cfg.CORSOrigins, want) {
+		t.Errorf("CORSOrigins = %q, want %q", cfg.CORSOrigins, want)
+	}
+
+	cfg, err = parseConfig([]string{"-addr", ":7000", "-max-body", "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":7000" || cfg.MaxBodyBytes != 10 {
+		t.Errorf("flags did not override env: addr %q, max body %d", cfg.Addr, cfg.MaxBodyBytes)
+	}
+	if cfg.Auth != "alice:s3cret" {
+		t.Errorf("Auth = %q, want the env value when no flag is given", cfg.Auth)
+	}
+}
+
+func TestParseConfigBadEnv(t *testing.T) {
+	t.Setenv("FRIEND_MAX_BODY", "lots")
+	_, err := parseConfig(nil)
+	if err == nil || !strings.Contains(err.Error(), "FRIEND_MAX_BODY") {
+		t.Fatalf("err = %v, want it to name FRIEND_MAX_BODY", err)
+	}
+
+	// The flag replaces the variable, so its bad value does not matter.
+	cfg, err := parseConfig([]string{"-max-body", "10"})
+	if err != nil {
+		t.Fatalf("parseConfig with -max-body: %v", err)
+	}
+	if cfg.MaxBodyBytes != 10 {
+		t.Errorf("MaxBodyBytes = %d, want 10 from the flag", cfg.MaxBodyBytes)
+	}
+
+	// A flag for a different setting does not excuse the bad variable.
+	if _, err := parseConfig([]string{"-workers", "2"}); err == nil || !strings.Contains(err.Error(), "FRIEND_MAX_BODY") {
+		t.Errorf("err = %v, want it to name FRIEND_MAX_BODY", err)
+	}
+}
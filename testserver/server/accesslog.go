@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotatingFile is an append-only log file that, once a write would take it
+// past maxBytes, is renamed to path+".1" (replacing any previous one) and
+// started afresh.
+type rotatingFile struct {
+	path     string
+	maxBytes int64
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxBytes int64) (*rotatingFile, error) {
+	rf := &rotatingFile{path: path, maxBytes: maxBytes}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("open access log: %w", err)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("open access log: %w", err)
+	}
+	rf.f, rf.size = f, fi.Size()
+	return nil
+}
+
+// Write appends p, rotating first if the file would grow past its limit. A
+// single record larger than the limit still goes into a fresh file whole.
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.size > 0 && rf.size+int64(len(p)) > rf.maxBytes {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.f.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.f.Close(); err != nil {
+		return fmt.Errorf("rotate access log: %w", err)
+	}
+	if err := os.Rename(rf.path, rf.path+".1"); err != nil {
+		return fmt.Errorf("rotate access log: %w", err)
+	}
+	return rf.open()
+}
+
+// Close closes the current file.
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.f.Close()
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	rf, err := openRotatingFile(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+
+	line := strings.Repeat("x", 39) + "\n"
+	for range 3 {
+		if _, err := rf.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, want := range map[string]int{path + ".1": 2 * len(line), path: len(line)} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != want {
+			t.Errorf("%s holds %d bytes, want %d", filepath.Base(name), len(data), want)
+		}
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	srv, err := NewServer(Config{AccessLog: path, AccessLogMaxBytes: 512, Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.accessLog.Close()
+
+	for range 10 {
+		srv.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/headers", nil))
+	}
+	for _, name := range []string{path, path + ".1"} {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "path=/headers") {
+			t.Errorf("%s = %q, want request log lines", filepath.Base(name), data)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"testserver/handler"
+)
+
+// Defaults applied to unset Config fields.
+const (
+	DefaultAddr            = ":8888"
+	DefaultLogFormat       = "text"
+	DefaultShutdownTimeout = 10 * time.Second
+
+	DefaultAccessLogMaxBytes = 10 << 20
+
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+
+	// DefaultWriteTimeout leaves room for the longest /delay on top of the
+	// time spent reading the request.
+	DefaultWriteTimeout = handler.MaxDelay + 30*time.Second
+)
+
+// Config holds everything needed to build and run a test server. The zero
+// value is valid; unset fields take the defaults above.
+type Config struct {
+	// Addr is the host:port to listen on, or "unix:" followed by the path
+	// of a Unix socket.
+	Addr string
+
+	// TLSCert and TLSKey name a certificate and key file. When both are
+	// set the server speaks HTTPS.
+	TLSCert string
+	TLSKey  string
+
+	// Listeners, when non-empty, replaces Addr, TLSCert and TLSKey with
+	// several addresses served at once by the same handlers.
+	Listeners []Listener
+
+	// H2C lets clients speak HTTP/2 without TLS by prior knowledge.
+	// HTTP/1.1 keeps working on the same listener.
+	H2C bool
+
+	// ShutdownTimeout bounds how long Run waits for in-flight requests to
+	// finish once a shutdown signal arrives.
+	ShutdownTimeout time.Duration
+
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout are
+	// passed to the http.Server so slow or idle clients cannot hold
+	// connections open forever.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// MaxBodyBytes caps the size of request bodies handlers will read.
+	MaxBodyBytes int64
+
+	// CORSOrigins lists the origins allowed to make cross-origin requests;
+	// "*" allows any. CORS handling is off when it is empty.
+	CORSOrigins []string
+
+	// Auth, in "user:password" form, requires those basic auth credentials
+	// on every request.
+	Auth string
+
+	// TrustProxy makes client address lookups prefer X-Forwarded-For and
+	// X-Real-IP over the connection's remote address.
+	TrustProxy bool
+
+	// RateLimit, when positive, caps requests per second with bursts of up
+	// to RateBurst (default: RateLimit rounded up). The limit is shared by
+	// all clients unless RateLimitPerIP is set.
+	RateLimit      float64
+	RateBurst      int
+	RateLimitPerIP bool
+
+	// Workers, when positive, caps how many requests are handled at once.
+	// Requests beyond that wait up to QueueTimeout for a free worker, or
+	// are rejected with 503 straight away when QueueTimeout is zero.
+	Workers      int
+	QueueTimeout time.Duration
+
+	// CaptureSize, when positive, keeps the last CaptureSize requests in
+	// memory for /debug/last.
+	CaptureSize int
+
+	// FailRate is the fraction of requests, from 0 to 1, answered with an
+	// injected 500 for chaos testing. Seed seeds the failure decisions so
+	// runs can be repeated; zero picks a random seed.
+	FailRate float64
+	Seed     int64
+
+	// ProxyTarget, when set, is an http or https URL that requests not
+	// matching a built-in endpoint are forwarded to, in place of the echo
+	// handler. ProxyHost replaces the client's Host header upstream; by
+	// default it is passed through unchanged.
+	ProxyTarget string
+	ProxyHost   string
+
+	// RoutesFile names a JSON file listing mock routes, each an object
+	// with path, method, status, headers and body, registered alongside
+	// the built-in endpoints. An empty method matches any method.
+	RoutesFile string
+
+	// LogFormat selects "text" or "json" output for the logger built when
+	// Logger is nil.
+	LogFormat string
+
+	// Logger receives all server logs, and request logs too unless
+	// AccessLog is set. When nil, a logger writing LogFormat records to
+	// stderr is used.
+	Logger *slog.Logger
+
+	// AccessLog names a file that request logs are written to, in
+	// LogFormat, instead of Logger. Once it would exceed
+	// AccessLogMaxBytes it is renamed with a ".1" suffix and a new file
+	// is started.
+	AccessLog         string
+	AccessLogMaxBytes int64
+}
+
+// Listener is one address the server accepts connections on.
+type Listener struct {
+	// Addr is a host:port, or "unix:" followed by a socket path.
+	Addr string `json:"addr"`
+
+	// TLSCert and TLSKey, when both set, make this listener speak HTTPS.
+	TLSCert string `json:"tls_cert"`
+	TLSKey  string `json:"tls_key"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.Addr == "" {
+		c.Addr = DefaultAddr
+	}
+	if len(c.Listeners) == 0 {
+		c.Listeners = []Listener{{Addr: c.Addr, TLSCert: c.TLSCert, TLSKey: c.TLSKey}}
+	}
+	if c.RateLimit > 0 && c.RateBurst == 0 {
+		c.RateBurst = int(math.Ceil(c.RateLimit))
+	}
+	if c.FailRate > 0 && c.Seed == 0 {
+		c.Seed = rand.Int64()
+	}
+	if c.LogFormat == "" {
+		c.LogFormat = DefaultLogFormat
+	}
+	if c.ShutdownTimeout == 0 {
+		c.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = DefaultReadHeaderTimeout
+	}
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = DefaultReadTimeout
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = DefaultWriteTimeout
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = DefaultIdleTimeout
+	}
+	if c.MaxBodyBytes == 0 {
+		c.MaxBodyBytes = handler.DefaultMaxBodyBytes
+	}
+	if c.AccessLogMaxBytes == 0 {
+		c.AccessLogMaxBytes = DefaultAccessLogMaxBytes
+	}
+	return c
+}
+
+func (c Config) validate() error {
+	for i, l := range c.Listeners {
+		if l.Addr == "" {
+			return fmt.Errorf("listener %d: address is empty", i)
+		}
+		if (l.TLSCert == "") != (l.TLSKey == "") {
+			return fmt.Errorf("listener %s: TLS certificate and key must be given together", l.Addr)
+		}
+	}
+	for name, d := range map[string]time.Duration{
+		"shutdown":    c.ShutdownTimeout,
+		"read header": c.ReadHeaderTimeout,
+		"read":        c.ReadTimeout,
+		"write":       c.WriteTimeout,
+		"idle":        c.IdleTimeout,
+	} {
+		if d < 0 {
+			return fmt.Errorf("%s timeout %s is negative", name, d)
+		}
+	}
+	if c.MaxBodyBytes < 0 {
+		return fmt.Errorf("max body size %d is negative", c.MaxBodyBytes)
+	}
+	if c.AccessLogMaxBytes < 0 {
+		return fmt.Errorf("access log size limit %d is negative", c.AccessLogMaxBytes)
+	}
+	if c.RateLimit < 0 || c.RateBurst < 0 {
+		return fmt.Errorf("rate limit %g with burst %d: must not be negative", c.RateLimit, c.RateBurst)
+	}
+	if c.Workers < 0 || c.QueueTimeout < 0 {
+		return fmt.Errorf("workers %d with queue timeout %s: must not be negative", c.Workers, c.QueueTimeout)
+	}
+	if c.CaptureSize < 0 {
+		return fmt.Errorf("capture size %d is negative", c.CaptureSize)
+	}
+	if c.FailRate < 0 || c.FailRate > 1 {
+		return fmt.Errorf("invalid fail rate %g: want a fraction between 0 and 1", c.FailRate)
+	}
+	if c.Auth != "" && !strings.Contains(c.Auth, ":") {
+		return errors.New("auth must be in user:password form")
+	}
+	if c.ProxyTarget != "" {
+		if _, err := parseProxyTarget(c.ProxyTarget); err != nil {
+			return err
+		}
+	}
+	if c.Logger == nil || c.AccessLog != "" {
+		if _, err := NewLogger(io.Discard, c.LogFormat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepare applies defaults, validates the result and fills in Logger.
+func (c Config) prepare() (Config, error) {
+	c = c.withDefaults()
+	if err := c.validate(); err != nil {
+		return c, err
+	}
+	if c.Logger == nil {
+		c.Logger, _ = NewLogger(os.Stderr, c.LogFormat)
+	}
+	return c, nil
+}
+
+// parseProxyTarget checks that target is an absolute http or https URL.
+func parseProxyTarget(target string) (*url.URL, error) {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy target %q: want an http:// or https:// URL", target)
+	}
+	return u, nil
+}
+
+// NewLogger returns a logger writing to w in the given format, "text" or
+// "json".
+func NewLogger(w io.Writer, format string) (*slog.Logger, error) {
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, nil)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, nil)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q: want text or json", format)
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewServerDefaults(t *testing.T) {
+	srv, err := NewServer(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.Addr != DefaultAddr {
+		t.Errorf("Addr = %q, want %q", srv.Addr, DefaultAddr)
+	}
+	if srv.Handler == nil || srv.Handler == http.DefaultServeMux {
+		t.Error("Handler is not wrapped in middleware")
+	}
+	if srv.TLSConfig != nil {
+		t.Error("TLS is enabled without a certificate")
+	}
+}
+
+func TestNewServerInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"cert without key", Config{TLSCert: "cert.pem"}},
+		{"key without cert", Config{TLSKey: "key.pem"}},
+		{"missing cert files", Config{TLSCert: "missing.pem", TLSKey: "missing.pem"}},
+		{"listener cert without key", Config{Listeners: []Listener{{Addr: ":0"}, {Addr: ":1", TLSCert: "cert.pem"}}}},
+		{"listener without address", Config{Listeners: []Listener{{}}}},
+		{"negative max body", Config{MaxBodyBytes: -1}},
+		{"fail rate above one", Config{FailRate: 1.5}},
+		{"auth without password", Config{Auth: "alice"}},
+		{"unknown log format", Config{LogFormat: "xml"}},
+		{"relative proxy target", Config{ProxyTarget: "upstream:8080"}},
+		{"non-http proxy target", Config{ProxyTarget: "ftp://upstream"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewServer(tt.cfg); err == nil {
+				t.Error("NewServer succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestNewServerTimeouts(t *testing.T) {
+	srv, err := NewServer(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.ReadHeaderTimeout != DefaultReadHeaderTimeout || srv.ReadTimeout != DefaultReadTimeout ||
+		srv.WriteTimeout != DefaultWriteTimeout || srv.IdleTimeout != DefaultIdleTimeout {
+		t.Errorf("default timeouts = %s/%s/%s/%s", srv.ReadHeaderTimeout, srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+
+	srv, err = NewServer(Config{
+		ReadHeaderTimeout: time.Second,
+		ReadTimeout:       2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.ReadHeaderTimeout != time.Second || srv.ReadTimeout != 2*time.Second ||
+		srv.WriteTimeout != 3*time.Second || srv.IdleTimeout != 4*time.Second {
+		t.Errorf("configured timeouts = %s/%s/%s/%s", srv.ReadHeaderTimeout, srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+}
+
+func TestReadHeaderTimeoutCutsOffSlowClient(t *testing.T) {
+	ln, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := NewServer(Config{ReadHeaderTimeout: 50 * time.Millisecond, Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.RunListener(ctx, ln)
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: slow\r\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The header block is never finished, so the server should give up and
+	// close the connection well before our own deadline.
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.ReadAll(conn); err != nil {
+		t.Fatalf("connection was not closed by the server: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// connTracker counts the server's connections by state. It is installed as
+// the http.Server's ConnState hook.
+type connTracker struct {
+	mu     sync.Mutex
+	states map[net.Conn]http.ConnState
+
+	open   atomic.Int64
+	active atomic.Int64
+	idle   atomic.Int64
+}
+
+// connCounts is the /debug/conns reply. Open counts every connection not yet
+// closed or hijacked, including new ones that have not sent a request.
+type connCounts struct {
+	Open   int64 `json:"open"`
+	Active int64 `json:"active"`
+	Idle   int64 `json:"idle"`
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{states: make(map[net.Conn]http.ConnState)}
+}
+
+// track records conn entering state, undoing the count for the state it
+// left. Remembering each connection's last state keeps every decrement paired
+// with an earlier increment, so no counter goes negative.
+func (t *connTracker) track(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if prev, ok := t.states[conn]; ok {
+		t.adjust(prev, -1)
+	}
+	if state == http.StateClosed || state == http.StateHijacked {
+		delete(t.states, conn)
+		return
+	}
+	t.states[conn] = state
+	t.adjust(state, 1)
+}
+
+func (t *connTracker) adjust(state http.ConnState, delta int64) {
+	t.open.Add(delta)
+	switch state {
+	case http.StateActive:
+		t.active.Add(delta)
+	case http.StateIdle:
+		t.idle.Add(delta)
+	}
+}
+
+func (t *connTracker) counts() connCounts {
+	return connCounts{Open: t.open.Load(), Active: t.active.Load(), Idle: t.idle.Load()}
+}
+
+// handleConns replies with the current connection counts as JSON.
+func (s *Server) handleConns(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.conns.counts())
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestConnTracking(t *testing.T) {
+	ln, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.RunListener(ctx, ln) }()
+	addr := ln.Addr().String()
+
+	// Two keep-alive connections that each finish a request and then sit
+	// idle.
+	var kept []net.Conn
+	for range 2 {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		req, _ := http.NewRequest(http.MethodGet, "http://"+addr+"/healthz", nil)
+		if err := req.Write(conn); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		kept = append(kept, conn)
+	}
+
+	// Each query comes over its own connection, which is active while it is
+	// being answered.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	waitForConns(t, client, addr, connCounts{Open: 3, Active: 1, Idle: 2})
+
+	for _, conn := range kept {
+		conn.Close()
+	}
+	waitForConns(t, client, addr, connCounts{Open: 1, Active: 1, Idle: 0})
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RunListener returned %v", err)
+	}
+}
+
+// waitForConns polls /debug/conns until it reports want, since the server
+// sees connection state changes slightly after the client does.
+func waitForConns(t *testing.T, client *http.Client, addr string, want connCounts) {
+	t.Helper()
+	var got connCounts
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		resp, err := client.Get("http://" + addr + "/debug/conns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&got)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got == want {
+			return
+		}
+	}
+	t.Fatalf("/debug/conns = %+v, want %+v", got, want)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestH2C(t *testing.T) {
+	ln, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := NewServer(Config{H2C: true, Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.RunListener(ctx, ln) }()
+
+	h2 := new(http.Protocols)
+	h2.SetUnencryptedHTTP2(true)
+	h1 := new(http.Protocols)
+	h1.SetHTTP1(true)
+	tests := []struct {
+		name      string
+		protocols *http.Protocols
+		want      string
+	}{
+		{"prior knowledge", h2, "HTTP/2.0"},
+		{"plain HTTP/1.1", h1, "HTTP/1.1"},
+	}
+	for _, tt := range tests {
+		client := &http.Client{Transport: &http.Transport{Protocols: tt.protocols}}
+		resp, err := client.Get("http://" + ln.Addr().String() + "/headers")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Proto != tt.want {
+			t.Errorf("%s: got %d over %s, want 200 over %s", tt.name, resp.StatusCode, resp.Proto, tt.want)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RunListener returned %v", err)
+	}
+}
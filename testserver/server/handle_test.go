@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHandle(t *testing.T) {
+	accessLog := filepath.Join(t.TempDir(), "access.log")
+	srv, err := NewServer(Config{AccessLog: accessLog, Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.accessLog.Close()
+	srv.HandleFunc("GET /mine", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("all mine"))
+	})
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/mine", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "all mine" {
+		t.Fatalf("GET /mine = %d %q, want the custom handler's reply", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Error("custom route skipped the request ID middleware")
+	}
+	data, err := os.ReadFile(accessLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "path=/mine") {
+		t.Errorf("access log = %q, want a /mine entry", data)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/elsewhere", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), `"/mine"`) {
+		t.Errorf("404 = %d %q, want it to list /mine", rec.Code, rec.Body)
+	}
+}
+
+func TestHandleConflictPanics(t *testing.T) {
+	srv, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("registering /headers again did not panic")
+		}
+	}()
+	srv.Handle("/headers", http.NotFoundHandler())
+}
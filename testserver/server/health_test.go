@@ -0,0 +1,30 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthProbes(t *testing.T) {
+	srv, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get("/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz = %d, want 200", code)
+	}
+	if code := get("/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz before MarkReady = %d, want 503", code)
+	}
+	srv.MarkReady()
+	if code := get("/readyz"); code != http.StatusOK {
+		t.Errorf("/readyz after MarkReady = %d, want 200", code)
+	}
+}
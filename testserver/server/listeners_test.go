@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logLines hands each log record written to it to the test as it arrives.
+type logLines chan []byte
+
+func (l logLines) Write(p []byte) (int, error) {
+	l <- append([]byte(nil), p...)
+	return len(p), nil
+}
+
+func TestRunMultipleListeners(t *testing.T) {
+	certFile, keyFile, pool := writeSelfSignedCert(t)
+	lines := make(logLines, 16)
+	srv, err := NewServer(Config{
+		Listeners: []Listener{{Addr: "127.0.0.1:0"}, {Addr: "127.0.0.1:0", TLSCert: certFile, TLSKey: keyFile}},
+		Logger:    slog.New(slog.NewJSONHandler(lines, nil)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	var urls []string
+	for len(urls) < 2 {
+		select {
+		case line := <-lines:
+			var entry struct{ Msg, URL string }
+			if err := json.Unmarshal(line, &entry); err != nil {
+				t.Fatal(err)
+			}
+			if entry.Msg == "server starting" {
+				urls = append(urls, entry.URL)
+			}
+		case err := <-done:
+			t.Fatalf("Run returned early: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("listeners did not start")
+		}
+	}
+	if !strings.HasPrefix(urls[0], "http://") || !strings.HasPrefix(urls[1], "https://") {
+		t.Fatalf("listener URLs = %q, want one plain and one TLS", urls)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DisableKeepAlives: true,
+		TLSClientConfig:   &tls.Config{RootCAs: pool},
+	}}
+	for _, url := range urls {
+		resp, err := client.Get(url + "/healthz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s/healthz = %d, want 200", url, resp.StatusCode)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+	for _, url := range urls {
+		if _, err := client.Get(url + "/healthz"); err == nil {
+			t.Errorf("%s still accepts requests after shutdown", url)
+		}
+	}
+}
+
+func TestRunReportsEveryBindFailure(t *testing.T) {
+	busy1, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy1.Close()
+	busy2, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy2.Close()
+
+	srv, err := NewServer(Config{
+		Listeners: []Listener{{Addr: busy1.Addr().String()}, {Addr: "127.0.0.1:0"}, {Addr: busy2.Addr().String()}},
+		Logger:    discardLogger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = srv.Run(context.Background())
+	if err == nil {
+		t.Fatal("Run succeeded, want bind errors")
+	}
+	for _, addr := range []string{busy1.Addr().String(), busy2.Addr().String()} {
+		if !strings.Contains(err.Error(), addr) {
+			t.Errorf("err = %v, want it to name %s", err, addr)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"testserver/handler"
+)
+
+// mockRoute is one entry of a routes file.
+type mockRoute struct {
+	Path    string            `json:"path"`
+	Method  string            `json:"method"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// loadMockRoutes reads a JSON array of mock routes from path. Every entry is
+// checked up front so a bad file stops the server from starting rather than
+// surfacing on the first request.
+func loadMockRoutes(path string) ([]route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("routes file: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var entries []mockRoute
+	if err := dec.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("routes file %s: %w", path, err)
+	}
+
+	rts := make([]route, 0, len(entries))
+	for i, e := range entries {
+		rt, err := e.route()
+		if err != nil {
+			return nil, fmt.Errorf("routes file %s: route %d: %w", path, i, err)
+		}
+		rts = append(rts, rt)
+	}
+	return rts, nil
+}
+
+func (e mockRoute) route() (route, error) {
+	if !strings.HasPrefix(e.Path, "/") {
+		return route{}, fmt.Errorf("invalid path %q: must start with /", e.Path)
+	}
+	if strings.ContainsAny(e.Method, " \t") || strings.ToUpper(e.Method) != e.Method {
+		return route{}, fmt.Errorf("invalid method %q: want an upper-case method such as GET", e.Method)
+	}
+	status := e.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status < 100 || status > 599 {
+		return route{}, fmt.Errorf("invalid status %d: want an integer between 100 and 599", e.Status)
+	}
+	header := make(http.Header, len(e.Headers))
+	for k, v := range e.Headers {
+		header.Set(k, v)
+	}
+
+	pattern := e.Path
+	if e.Method != "" {
+		pattern = e.Method + " " + e.Path
+	}
+	return route{pattern, handler.MockHandler(status, header, e.Body)}, nil
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRoutesFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "routes.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRoutesFile(t *testing.T) {
+	path := writeRoutesFile(t, `[
+		{"path": "/users/1", "method": "GET", "status": 200,
+		 "headers": {"Content-Type": "application/json"}, "body": "{\"id\":1}"},
+		{"path": "/teapot", "status": 418, "body": "short and stout"}
+	]`)
+	srv, err := NewServer(Config{RoutesFile: path, Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{http.MethodGet, "/users/1", http.StatusOK, `{"id":1}`},
+		{http.MethodPost, "/teapot", http.StatusTeapot, "short and stout"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.status || rec.Body.String() != tt.body {
+			t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.path, rec.Code, rec.Body, tt.status, tt.body)
+		}
+	}
+}
+
+func TestRoutesFileInvalid(t *testing.T) {
+	tests := []struct {
+		name, content string
+	}{
+		{"malformed JSON", `[{"path": "/a"`},
+		{"unknown field", `[{"path": "/a", "code": 200}]`},
+		{"relative path", `[{"path": "a"}]`},
+		{"lower-case method", `[{"path": "/a", "method": "get"}]`},
+		{"bad status", `[{"path": "/a", "status": 99}]`},
+		{"duplicate route", `[{"path": "/a"}, {"path": "/a"}]`},
+		{"shadows built-in", `[{"path": "/headers"}]`},
+		{"shadows fallback", `[{"path": "/"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeRoutesFile(t, tt.content)
+			for _, cfg := range []Config{
+				{RoutesFile: path, Logger: discardLogger},
+				{RoutesFile: path, ProxyTarget: "http://upstream.test", Logger: discardLogger},
+			} {
+				if _, err := NewServer(cfg); err == nil {
+					t.Errorf("NewServer(proxy target %q) succeeded, want an error", cfg.ProxyTarget)
+				}
+			}
+		})
+	}
+
+	if _, err := NewServer(Config{RoutesFile: "missing.json", Logger: discardLogger}); err == nil {
+		t.Error("NewServer with a missing routes file succeeded, want an error")
+	}
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProxyTarget(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("from upstream " + r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	var logs bytes.Buffer
+	srv, err := NewServer(Config{ProxyTarget: upstream.URL, Logger: slog.New(slog.NewJSONHandler(&logs, nil))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"/", "/api/items"} {
+		logs.Reset()
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if want := "from upstream " + path; rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("GET %s = %d %q, want %q", path, rec.Code, rec.Body, want)
+		}
+
+		var entry map[string]any
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("log for %s is not one JSON record: %v\n%s", path, err, logs.String())
+		}
+		if entry["msg"] != "request" || entry["path"] != path || entry["status"] != float64(http.StatusOK) {
+			t.Errorf("log for %s = %v, want a logged 200", path, entry)
+		}
+	}
+
+	// Built-in endpoints are still served locally.
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok\n" {
+		t.Errorf("GET /healthz = %d %q, want the local probe", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPanicThroughMiddleware(t *testing.T) {
+	srv, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.HandleFunc("/boom", func(http.ResponseWriter, *http.Request) { panic("deliberate") })
+
+	for _, ae := range []string{"", "gzip", "br"} {
+		req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+		req.Header.Set("Accept-Encoding", ae)
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, req)
+
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Accept-Encoding %q: body %q is not JSON: %v", ae, rec.Body, err)
+		}
+		if rec.Code != http.StatusInternalServerError || body["error"] != "internal server error" {
+			t.Errorf("Accept-Encoding %q: response = %d %v, want 500 with a JSON error", ae, rec.Code, body)
+		}
+		if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want none", ae, ce)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"testserver/handler"
+)
+
+// route is one built-in or mock endpoint.
+type route struct {
+	pattern string
+	handler http.Handler
+}
+
+func (s *Server) routes() []route {
+	rts := []route{
+		{"/headers", http.HandlerFunc(handler.HandleHeaders)},
+		{"/status/{code}", http.HandlerFunc(handler.HandleStatus)},
+		{"/delay/{seconds}", http.HandlerFunc(handler.HandleDelay)},
+		{"/drip", http.HandlerFunc(handler.HandleDrip)},
+		{"/json", http.HandlerFunc(handler.HandleJSON)},
+		{"/stream/{n}", http.HandlerFunc(handler.HandleStream)},
+		{"/ws", http.HandlerFunc(handler.HandleWebSocket)},
+		{"/redirect/{n}", http.HandlerFunc(handler.HandleRedirect)},
+		{"/bytes/{n}", http.HandlerFunc(handler.HandleBytes)},
+		{"/cache", http.HandlerFunc(handler.HandleCache)},
+		{"/cache-control", http.HandlerFunc(handler.HandleCacheControl)},
+		{"/cookies", http.HandlerFunc(handler.HandleCookies)},
+		{"/cookies/set", http.HandlerFunc(handler.HandleSetCookies)},
+		{"/cookies/delete", http.HandlerFunc(handler.HandleDeleteCookies)},
+		{"/gzip-decode", http.HandlerFunc(handler.HandleGzipDecode)},
+		{"/encoding", http.HandlerFunc(handler.HandleEncoding)},
+		{"/anything", http.HandlerFunc(handler.HandleAnything)},
+		{"/anything/", http.HandlerFunc(handler.HandleAnything)},
+		{"/form", http.HandlerFunc(handler.HandleForm)},
+		{"/uuid", http.HandlerFunc(handler.HandleUUID)},
+		{"/base64/{value}", http.HandlerFunc(handler.HandleBase64)},
+		{"/ip", handler.IPHandler(s.cfg.TrustProxy)},
+		{"/metrics", s.metrics},
+		{"/healthz", http.HandlerFunc(s.handleHealthz)},
+		{"/readyz", http.HandlerFunc(s.handleReadyz)},
+		{"/debug/conns", http.HandlerFunc(s.handleConns)},
+	}
+	if s.capture != nil {
+		rts = append(rts, route{"/debug/last", s.capture})
+	}
+	if s.cfg.ProxyTarget == "" {
+		rts = append([]route{{"/{$}", http.HandlerFunc(handler.HandleEcho)}}, rts...)
+	}
+	return rts
+}
+
+// newMux registers the mock and built-in routes on a fresh mux. Anything that
+// matches none of them, or of the routes added later with Handle, gets a JSON
+// 404 listing the endpoints, or is forwarded upstream when a proxy target is
+// configured. A mock route whose pattern conflicts with another route or with
+// that fallback is reported as an error.
+func (s *Server) newMux() (*http.ServeMux, error) {
+	mux := http.NewServeMux()
+	for _, rt := range append(s.mocks, s.routes()...) {
+		if err := handle(mux, rt); err != nil {
+			return nil, err
+		}
+		s.endpoints = append(s.endpoints, endpoint(rt.pattern))
+	}
+	var fallback http.Handler
+	if s.cfg.ProxyTarget != "" {
+		target, _ := parseProxyTarget(s.cfg.ProxyTarget)
+		fallback = handler.ProxyHandler(target, s.cfg.ProxyHost, s.cfg.Logger)
+	} else {
+		// Looked up per request so the listing includes routes added by
+		// Handle.
+		fallback = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.NotFoundHandler(s.endpoints).ServeHTTP(w, r)
+		})
+	}
+	// A mock route for "/" with no method would take the fallback's place.
+	if err := handle(mux, route{"/", fallback}); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
+
+// endpoint returns the path a pattern is listed under in 404 replies.
+func endpoint(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	return strings.TrimSuffix(pattern, "{$}")
+}
+
+// Handle registers h for pattern, using http.ServeMux pattern syntax,
+// alongside the built-in routes. It must be called before Run or
+// RunListener. Like http.ServeMux.Handle, it panics if pattern is invalid or
+// conflicts with a route already registered, including the "/" fallback.
+//
+// Requests to h pass through the same middleware as the built-in routes. From
+// outermost to innermost: request ID, request logging, panic recovery,
+// request capture, CORS, rate limiting, basic auth, the concurrency limit,
+// chaos failures, response compression and the request body limit. Optional
+// layers are skipped when their Config field leaves them off.
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, h)
+	s.endpoints = append(s.endpoints, endpoint(pattern))
+}
+
+// HandleFunc is like Handle for a plain handler function.
+func (s *Server) HandleFunc(pattern string, f func(http.ResponseWriter, *http.Request)) {
+	s.Handle(pattern, http.HandlerFunc(f))
+}
+
+// handle registers rt on mux, turning the panic ServeMux raises for an invalid
+// or conflicting pattern into an error.
+func handle(mux *http.ServeMux, rt route) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("route %q: %v", rt.pattern, v)
+		}
+	}()
+	mux.Handle(rt.pattern, rt.handler)
+	return nil
+}
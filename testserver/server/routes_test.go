@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+func TestNotFound(t *testing.T) {
+	srv, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/does-not-exist", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body struct {
+		Error     string
+		Path      string
+		Endpoints []string
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error != "not found" || body.Path != "/does-not-exist" {
+		t.Errorf("body = %+v, want not found for /does-not-exist", body)
+	}
+	if !slices.Contains(body.Endpoints, "/") || !slices.Contains(body.Endpoints, "/headers") {
+		t.Errorf("endpoints = %q, want the registered routes", body.Endpoints)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Hello from Go HTTP Server!") {
+		t.Errorf("GET / = %d %q, want the echo response", rec.Code, rec.Body)
+	}
+}
+
+func TestContentLengthSurvivesCompression(t *testing.T) {
+	srv, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range []string{"/bytes/100", "/drip?duration=0&numbytes=100"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, req)
+
+		if ce := rec.Header().Get("Content-Encoding"); ce != "" {
+			t.Errorf("%s: Content-Encoding = %q, want none", target, ce)
+		}
+		if cl := rec.Header().Get("Content-Length"); cl != "100" || rec.Body.Len() != 100 {
+			t.Errorf("%s: Content-Length %q with %d body bytes, want 100", target, cl, rec.Body.Len())
+		}
+	}
+}
+
+func TestMetricsPerServer(t *testing.T) {
+	busy, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	quiet, err := NewServer(Config{Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		busy.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	quiet.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), "\nhttp_requests_total 0\n") {
+		t.Errorf("quiet server metrics = %q, want no requests counted", rec.Body)
+	}
+}
@@ -0,0 +1,270 @@
+// Package server runs the test server and shuts it down gracefully.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"testserver/handler"
+)
+
+// Server is a test server built from a Config. The embedded *http.Server is
+// fully wired and may be inspected, but should be started with Run or
+// RunListener so shutdown is handled.
+type Server struct {
+	*http.Server
+	cfg       Config
+	listeners []listener
+	mux       *http.ServeMux
+	endpoints []string
+	mocks     []route
+	capture   *handler.Capture
+	metrics   *handler.Metrics
+	conns     *connTracker
+	ready     atomic.Bool
+
+	accessLog *rotatingFile
+	requests  *slog.Logger
+}
+
+// NewServer validates cfg and returns a Server serving the mock and built-in
+// routes behind the configured middleware. More routes can be added with
+// Handle before the server is run.
+func NewServer(cfg Config) (*Server, error) {
+	cfg, err := cfg.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{cfg: cfg, requests: cfg.Logger, conns: newConnTracker(), metrics: &handler.Metrics{}}
+	if cfg.CaptureSize > 0 {
+		s.capture = handler.NewCapture(cfg.CaptureSize)
+	}
+	if cfg.RoutesFile != "" {
+		if s.mocks, err = loadMockRoutes(cfg.RoutesFile); err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range cfg.Listeners {
+		tlsConfig, err := loadTLSConfig(l)
+		if err != nil {
+			return nil, err
+		}
+		s.listeners = append(s.listeners, listener{l.Addr, tlsConfig})
+	}
+	// The access log is opened last so no earlier failure leaks the file.
+	if cfg.AccessLog != "" {
+		if s.accessLog, err = openRotatingFile(cfg.AccessLog, cfg.AccessLogMaxBytes); err != nil {
+			return nil, err
+		}
+		s.requests, _ = NewLogger(s.accessLog, cfg.LogFormat)
+	}
+	h, err := s.newHandler()
+	if err != nil {
+		if s.accessLog != nil {
+			s.accessLog.Close()
+		}
+		return nil, err
+	}
+	s.Server = &http.Server{
+		Addr:              s.listeners[0].addr,
+		Handler:           h,
+		TLSConfig:         s.listeners[0].tls,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(cfg.Logger.Handler(), slog.LevelError),
+		ConnState:         s.conns.track,
+	}
+	if cfg.H2C {
+		s.Protocols = new(http.Protocols)
+		s.Protocols.SetHTTP1(true)
+		s.Protocols.SetHTTP2(true)
+		s.Protocols.SetUnencryptedHTTP2(true)
+	}
+	return s, nil
+}
+
+// MarkReady makes /readyz report success. Call it once any startup warmup is
+// done; until then the server is live but not ready.
+func (s *Server) MarkReady() {
+	s.ready.Store(true)
+}
+
+// listener is a configured address and, for HTTPS, its TLS settings.
+type listener struct {
+	addr string
+	tls  *tls.Config
+}
+
+// loadTLSConfig reads l's key pair, returning nil when l is plain HTTP.
+func loadTLSConfig(l Listener) (*tls.Config, error) {
+	if l.TLSCert == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(l.TLSCert, l.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS key pair for %s: %w", l.Addr, err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, NextProtos: []string{"h2", "http/1.1"}}, nil
+}
+
+// boundListener is a listener whose address is open for connections.
+type boundListener struct {
+	ln  net.Listener
+	tls *tls.Config
+}
+
+// Run listens on every configured address and serves until ctx is done or
+// the process receives SIGINT or SIGTERM. If any address cannot be bound,
+// none are served and the error lists every failure.
+func (s *Server) Run(ctx context.Context) error {
+	var (
+		bound []boundListener
+		errs  []error
+	)
+	for _, l := range s.listeners {
+		ln, err := Listen(l.addr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		bound = append(bound, boundListener{ln, l.tls})
+	}
+	if len(errs) > 0 {
+		for _, b := range bound {
+			b.ln.Close()
+		}
+		return errors.Join(errs...)
+	}
+	return s.serve(ctx, bound)
+}
+
+// RunListener is like Run but accepts connections on ln, using the first
+// configured listener's TLS settings, instead of listening on the configured
+// addresses.
+func (s *Server) RunListener(ctx context.Context, ln net.Listener) error {
+	return s.serve(ctx, []boundListener{{ln, s.TLSConfig}})
+}
+
+// serve accepts connections on every listener until ctx is done, a signal
+// arrives or one of them fails. All listeners then shut down together,
+// draining in-flight requests for up to the configured shutdown timeout.
+func (s *Server) serve(ctx context.Context, bound []boundListener) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, len(bound))
+	for _, b := range bound {
+		scheme, ln := "http", b.ln
+		if b.tls != nil {
+			scheme, ln = "https", tls.NewListener(b.ln, b.tls)
+		}
+		go func() { errc <- s.Serve(ln) }()
+		s.cfg.Logger.Info("server starting", "url", listenURL(scheme, b.ln))
+	}
+
+	var errs []error
+	pending := len(bound)
+	select {
+	case err := <-errc:
+		pending--
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, err)
+		}
+	case <-ctx.Done():
+	}
+
+	conns := s.conns.counts()
+	s.cfg.Logger.Info("shutting down",
+		"wait_seconds", s.cfg.ShutdownTimeout.Seconds(),
+		"open_conns", conns.Open,
+		"active_conns", conns.Active,
+	)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("shutdown: %w", err))
+	}
+	if s.accessLog != nil {
+		s.accessLog.Close()
+	}
+	// Shutdown closes the listeners first, so every Serve returns promptly.
+	for range pending {
+		if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Listen opens a TCP listener on addr, or a Unix socket listener when addr
+// has the form "unix:/path/to.sock". Bind failures are reported without the
+// raw net.OpError noise so they can be shown to the user as-is.
+func Listen(addr string) (net.Listener, error) {
+	if isUnixAddr(addr) {
+		return listenUnix(strings.TrimPrefix(addr, unixPrefix))
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		if errors.Is(err, syscall.EADDRINUSE) {
+			return nil, fmt.Errorf("cannot listen on %s: address already in use", addr)
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) {
+			err = opErr.Err
+		}
+		return nil, fmt.Errorf("cannot listen on %s: %w", addr, err)
+	}
+	return ln, nil
+}
+
+// newHandler wraps the route mux in the middleware the config asks for. The
+// order here is the one documented on Handle.
+func (s *Server) newHandler() (http.Handler, error) {
+	cfg := s.cfg
+	var err error
+	if s.mux, err = s.newMux(); err != nil {
+		return nil, err
+	}
+	var h http.Handler = s.mux
+	h = handler.MaxBodyMiddleware(cfg.MaxBodyBytes)(h)
+	h = handler.CompressMiddleware(h)
+	if cfg.FailRate > 0 {
+		h = handler.ChaosMiddleware(cfg.FailRate, uint64(cfg.Seed))(h)
+	}
+	if cfg.Workers > 0 {
+		h = handler.ConcurrencyMiddleware(cfg.Workers, cfg.QueueTimeout)(h)
+	}
+	if cfg.Auth != "" {
+		user, password, _ := strings.Cut(cfg.Auth, ":")
+		h = handler.BasicAuthMiddleware(user, password)(h)
+	}
+	if cfg.RateLimit > 0 {
+		var key func(*http.Request) string
+		if cfg.RateLimitPerIP {
+			key = func(r *http.Request) string { return handler.ClientIP(r, cfg.TrustProxy) }
+		}
+		h = handler.RateLimitMiddleware(cfg.RateLimit, cfg.RateBurst, key)(h)
+	}
+	if len(cfg.CORSOrigins) > 0 {
+		h = handler.CORSMiddleware(cfg.CORSOrigins)(h)
+	}
+	if s.capture != nil {
+		h = s.capture.Middleware(h)
+	}
+	h = handler.RecoverMiddleware(cfg.Logger)(h)
+	h = handler.LoggingMiddleware(s.requests, s.metrics)(h)
+	return handler.RequestIDMiddleware(h), nil
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// unixPrefix marks an address as a Unix socket path rather than host:port.
+const unixPrefix = "unix:"
+
+// listenUnix opens a Unix socket listener at path. A socket file left behind
+// by a previous run is removed first, but one that still accepts connections
+// is reported as in use. The socket file is removed again when the listener
+// is closed.
+func listenUnix(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("cannot listen on %s%s: file exists and is not a socket", unixPrefix, path)
+		}
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("cannot listen on %s%s: address already in use", unixPrefix, path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) {
+			err = opErr.Err
+		}
+		return nil, fmt.Errorf("cannot listen on %s%s: %w", unixPrefix, path, err)
+	}
+	return ln, nil
+}
+
+// listenURL describes ln for the startup log line.
+func listenURL(scheme string, ln net.Listener) string {
+	if ln.Addr().Network() == "unix" {
+		return unixPrefix + ln.Addr().String()
+	}
+	return scheme + "://" + ln.Addr().String()
+}
+
+// isUnixAddr reports whether addr names a Unix socket.
+func isUnixAddr(addr string) bool {
+	return strings.HasPrefix(addr, unixPrefix)
+}
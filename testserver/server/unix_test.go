@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "friend.sock")
+	// Leave a stale socket behind, as a crashed run would.
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	srv, err := NewServer(Config{Addr: "unix:" + path, Logger: discardLogger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := Listen(srv.Addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.RunListener(ctx, ln) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://friend/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "Hello from Go HTTP Server!") {
+		t.Errorf("body = %q, want the echo response", body)
+	}
+
+	if _, err := Listen(srv.Addr); err == nil || !strings.Contains(err.Error(), "address already in use") {
+		t.Errorf("second Listen err = %v, want address already in use", err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RunListener returned %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file still exists after shutdown: %v", err)
+	}
+}
+
+func TestUnixSocketNotASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "friend.sock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen("unix:" + path); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Fatalf("err = %v, want a not-a-socket error", err)
+	}
+}